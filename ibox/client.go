@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-version"
 	"io"
 	"io/ioutil"
 	"log"
@@ -12,6 +13,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 type Client struct {
@@ -20,6 +22,24 @@ type Client struct {
 	Hostname string
 	Http     *http.Client
 	// AuthToken string
+
+	// LogHttpBodies enables logging of the (redacted) request and response bodies
+	LogHttpBodies bool
+
+	// DefaultMetadata is merged into the metadata of every object the provider manages
+	DefaultMetadata map[string]string
+
+	// RecordEventIds stores the ids of the events caused by create and update in the state
+	RecordEventIds bool
+
+	limiter  *rateLimiter
+	inflight semaphore
+
+	// eventsLock serializes changes while their event ids are recorded
+	eventsLock sync.Mutex
+
+	// version is the software version of the array, nil when it is not known
+	version *version.Version
 }
 
 type ApiError struct {
@@ -106,6 +126,7 @@ type Pool struct {
 	Physical_capacity_critical  int          `json:"physical_capacity_critical,omitempty"`
 	Physical_capacity_warning   int          `json:"physical_capacity_warning,omitempty"`
 	Reserved_capacity           int          `json:"reserved_capacity,omitempty"`
+	Free_virtual_capacity       int          `json:"free_virtual_capacity,omitempty"`
 	Ssd_enabled                 bool         `json:"ssd_enabled,omitempty"`
 	Compression_enabled         bool         `json:"compression_enabled,omitempty"`
 	Max_extend                  int          `json:"max_extend,omitempty"`
@@ -151,6 +172,224 @@ type Volume struct {
 	Write_protected        bool   `json:"write_protected,omitempty"`
 }
 
+type Metadata struct {
+	Id          int    `json:"id,omitempty"`
+	Object_id   int    `json:"object_id,omitempty"`
+	Object_type string `json:"object_type,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+type Initiator_target struct {
+	Address string `json:"address,omitempty"`
+	Node_id int    `json:"node_id,omitempty"`
+	Port_id int    `json:"port_id,omitempty"`
+	Fabric  string `json:"fabric,omitempty"`
+}
+
+type Initiator struct {
+	Address string             `json:"address,omitempty"`
+	Host_id int                `json:"host_id,omitempty"`
+	Type    string             `json:"type,omitempty"`
+	Targets []Initiator_target `json:"targets,omitempty"`
+}
+
+type Network_config struct {
+	Default_gateway string `json:"default_gateway,omitempty"`
+	Netmask         int    `json:"netmask,omitempty"`
+	Network         string `json:"network,omitempty"`
+}
+
+type Network_space_ip struct {
+	Ip_address string `json:"ip_address,omitempty"`
+	Enabled    bool   `json:"enabled,omitempty"`
+	Type       string `json:"type,omitempty"`
+}
+
+type Network_space struct {
+	Id             int                `json:"id,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Service        string             `json:"service,omitempty"`
+	Interfaces     []int              `json:"interfaces,omitempty"`
+	Ips            []Network_space_ip `json:"ips,omitempty"`
+	Network_config *Network_config    `json:"network_config,omitempty"`
+	Mtu            int                `json:"mtu,omitempty"`
+	Rate_limit     int                `json:"rate_limit,omitempty"`
+}
+
+type Network_interface struct {
+	Id      int      `json:"id,omitempty"`
+	Node_id int      `json:"node_id,omitempty"`
+	Type    string   `json:"type,omitempty"`
+	Ports   []string `json:"ports,omitempty"`
+	Vlan_id int      `json:"vlan_id,omitempty"`
+	State   string   `json:"state,omitempty"`
+}
+
+type Smb_share_permission struct {
+	Id      int    `json:"id,omitempty"`
+	Sid     string `json:"sid,omitempty"`
+	Account string `json:"account,omitempty"`
+	Access  string `json:"access,omitempty"`
+}
+
+type Smb_share struct {
+	Id                       int                    `json:"id,omitempty"`
+	Name                     string                 `json:"name,omitempty"`
+	Filesystem_id            int                    `json:"filesystem_id,omitempty"`
+	Inner_path               string                 `json:"inner_path,omitempty"`
+	Access_based_enumeration bool                   `json:"access_based_enumeration,omitempty"`
+	Offline_caching          string                 `json:"offline_caching,omitempty"`
+	Permissions              []Smb_share_permission `json:"permissions,omitempty"`
+}
+
+type Smb_user struct {
+	Id               int    `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	Password         string `json:"password,omitempty"`
+	Enabled          bool   `json:"enabled,omitempty"`
+	Primary_group_id int    `json:"primary_group_id,omitempty"`
+	Uid              int    `json:"uid,omitempty"`
+}
+
+type Smb_group struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Gid  int    `json:"gid,omitempty"`
+}
+
+type Treeq struct {
+	Id            int    `json:"id,omitempty"`
+	Filesystem_id int    `json:"filesystem_id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Path          string `json:"path,omitempty"`
+	Soft_capacity int    `json:"soft_capacity,omitempty"`
+	Hard_capacity int    `json:"hard_capacity,omitempty"`
+	Soft_inodes   int    `json:"soft_inodes,omitempty"`
+	Hard_inodes   int    `json:"hard_inodes,omitempty"`
+	Used_capacity int    `json:"used_capacity,omitempty"`
+	Used_inodes   int    `json:"used_inodes,omitempty"`
+}
+
+type Nfs_quota struct {
+	Id            int  `json:"id,omitempty"`
+	Filesystem_id int  `json:"filesystem_id,omitempty"`
+	Uid           *int `json:"uid,omitempty"`
+	Gid           *int `json:"gid,omitempty"`
+	Soft_capacity int  `json:"soft_capacity,omitempty"`
+	Hard_capacity int  `json:"hard_capacity,omitempty"`
+	Soft_inodes   int  `json:"soft_inodes,omitempty"`
+	Hard_inodes   int  `json:"hard_inodes,omitempty"`
+	Used_capacity int  `json:"used_capacity,omitempty"`
+	Used_inodes   int  `json:"used_inodes,omitempty"`
+}
+
+type User struct {
+	Id       int    `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Password string `json:"password,omitempty"`
+	Enabled  bool   `json:"enabled,omitempty"`
+}
+
+type Ldap_schema struct {
+	User_class               string `json:"user_class,omitempty"`
+	Username_attribute       string `json:"username_attribute,omitempty"`
+	Group_class              string `json:"group_class,omitempty"`
+	Groupname_attribute      string `json:"groupname_attribute,omitempty"`
+	Group_memberof_attribute string `json:"group_memberof_attribute,omitempty"`
+	User_search_base_dn      string `json:"user_search_base_dn,omitempty"`
+	Group_search_base_dn     string `json:"group_search_base_dn,omitempty"`
+}
+
+type Ldap_config struct {
+	Id                int          `json:"id,omitempty"`
+	Name              string       `json:"name,omitempty"`
+	Repository_type   string       `json:"repository_type,omitempty"`
+	Domain_name       string       `json:"domain_name,omitempty"`
+	Servers           []string     `json:"servers,omitempty"`
+	Ldap_port         int          `json:"ldap_port,omitempty"`
+	Use_ldaps         bool         `json:"use_ldaps,omitempty"`
+	Bind_username     string       `json:"bind_username,omitempty"`
+	Bind_password     string       `json:"bind_password,omitempty"`
+	Schema_definition *Ldap_schema `json:"schema_definition,omitempty"`
+}
+
+// Ldap_group maps the members of a directory group to a role, it is a user of type Ldap on the array
+type Ldap_group struct {
+	Id              int    `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Role            string `json:"role,omitempty"`
+	Ldap_group_name string `json:"ldap_group_name,omitempty"`
+	Ldap_group_dn   string `json:"ldap_group_dn,omitempty"`
+	Ldap_config_id  int    `json:"ldap_config_id,omitempty"`
+}
+
+type Notification_target struct {
+	Id           int    `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Protocol     string `json:"protocol,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Port         int    `json:"port,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+	From_address string `json:"from_address,omitempty"`
+	Tls          bool   `json:"tls,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Community    string `json:"community,omitempty"`
+	Transport    string `json:"transport,omitempty"`
+	Facility     string `json:"facility,omitempty"`
+}
+
+type Notification_rule_target struct {
+	Target_id  int      `json:"target_id,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+type Notification_rule struct {
+	Id                int                        `json:"id,omitempty"`
+	Name              string                     `json:"name,omitempty"`
+	Event_level       []string                   `json:"event_level,omitempty"`
+	Include_events    []string                   `json:"include_events,omitempty"`
+	Event_visibility  []string                   `json:"event_visibility,omitempty"`
+	Target_parameters []Notification_rule_target `json:"target_parameters,omitempty"`
+}
+
+type System_capacity struct {
+	Total_physical_capacity int `json:"total_physical_capacity,omitempty"`
+	Total_virtual_capacity  int `json:"total_virtual_capacity,omitempty"`
+	Free_physical_space     int `json:"free_physical_space,omitempty"`
+	Free_virtual_space      int `json:"free_virtual_space,omitempty"`
+}
+
+type System_operational_state struct {
+	State       string `json:"state,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type System struct {
+	Name              string                    `json:"name,omitempty"`
+	Serial_number     int                       `json:"serial_number,omitempty"`
+	Model_name        string                    `json:"model_name,omitempty"`
+	Version           string                    `json:"version,omitempty"`
+	Capacity          *System_capacity          `json:"capacity,omitempty"`
+	Operational_state *System_operational_state `json:"operational_state,omitempty"`
+}
+
+type Event struct {
+	Id          int    `json:"id,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Level       string `json:"level,omitempty"`
+	Description string `json:"description,omitempty"`
+	Reporter    string `json:"reporter,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+	Timestamp   int64  `json:"timestamp,omitempty"`
+}
+
 // NewClient returns a new iBox API client
 func NewClient(username string, password string, hostname string) (*Client, error) {
 	client := Client{
@@ -163,6 +402,12 @@ func NewClient(username string, password string, hostname string) (*Client, erro
 	return &client, nil
 }
 
+// SetThrottle configures client side rate limiting, zero values disable the limits
+func (client *Client) SetThrottle(requests_per_second float64, burst int, max_concurrent_requests int) {
+	client.limiter = newRateLimiter(requests_per_second, burst)
+	client.inflight = newSemaphore(max_concurrent_requests)
+}
+
 // Creates a new request with necessary headers
 func (c *Client) newRequest(method string, endpoint string, body []byte) (*http.Request, error) {
 
@@ -204,11 +449,11 @@ func (client *Client) apiCall(method string, endpoint string, data []byte) (*Api
 		return nil, nil, fmt.Errorf("[ERROR] %v", err)
 	}
 
-	requestDump, err := httputil.DumpRequest(req, true)
-	if err != nil {
-		fmt.Println(err)
-	}
-	log.Printf("[DEBUG] HTTP REQUEST: \n%v", string(requestDump))
+	client.inflight.Acquire()
+	defer client.inflight.Release()
+	client.limiter.Wait()
+
+	client.logRequest(req, data)
 
 	resp, err := client.Http.Do(req)
 	if err != nil {
@@ -217,17 +462,13 @@ func (client *Client) apiCall(method string, endpoint string, data []byte) (*Api
 
 	defer resp.Body.Close()
 
-	responseDump, err := httputil.DumpResponse(resp, true)
-	if err != nil {
-		fmt.Errorf("[ERROR] dumping HTTP response: %v", err)
-	}
-	log.Printf("[DEBUG] HTTP RESPONSE: \n%v", string(responseDump))
-
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, nil, fmt.Errorf("[ERROR] %v", err)
 	}
 
+	client.logResponse(resp, body)
+
 	var apiresult ApiResult
 	err = json.Unmarshal(body, &apiresult)
 	if err != nil {
@@ -236,6 +477,58 @@ func (client *Client) apiCall(method string, endpoint string, data []byte) (*Api
 	return &apiresult, resp, nil
 }
 
+// apiGetAll fetches every page of a collection, the endpoint must already contain a query string
+func (client *Client) apiGetAll(endpoint string) ([]json.RawMessage, error) {
+	var pages []json.RawMessage
+	for page := 1; ; page++ {
+		apiresult, resp, err := client.apiCall("GET", endpoint+"&page_size=1000&page="+strconv.Itoa(page), nil)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] %v", err)
+		}
+		if resp.StatusCode != 200 {
+			out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+			return nil, fmt.Errorf("[ERROR] %v", string(out))
+		}
+		if apiresult.Result != nil {
+			pages = append(pages, *apiresult.Result)
+		}
+		if apiresult.Metadata == nil || page >= apiresult.Metadata.Pages_total {
+			return pages, nil
+		}
+	}
+}
+
+// logRequest dumps the request with sensitive headers masked, the body is logged only if enabled
+func (client *Client) logRequest(req *http.Request, body []byte) {
+	dumpReq := *req
+	dumpReq.Header = redactHeader(req.Header)
+	requestDump, err := httputil.DumpRequest(&dumpReq, false)
+	if err != nil {
+		log.Printf("[WARN] dumping HTTP request: %v", err)
+		return
+	}
+	if client.LogHttpBodies {
+		requestDump = append(requestDump, redactBody(body)...)
+	}
+	log.Printf("[DEBUG] HTTP REQUEST: \n%v", string(requestDump))
+}
+
+// logResponse dumps the response with sensitive headers masked, the body is logged only if enabled
+func (client *Client) logResponse(resp *http.Response, body []byte) {
+	dumpResp := *resp
+	dumpResp.Header = redactHeader(resp.Header)
+	dumpResp.Body = nil
+	responseDump, err := httputil.DumpResponse(&dumpResp, false)
+	if err != nil {
+		log.Printf("[WARN] dumping HTTP response: %v", err)
+		return
+	}
+	if client.LogHttpBodies {
+		responseDump = append(responseDump, redactBody(body)...)
+	}
+	log.Printf("[DEBUG] HTTP RESPONSE: \n%v", string(responseDump))
+}
+
 func (client *Client) CreateHost(host Host) (*Host, error) {
 
 	reqBody, err := json.MarshalIndent(host, "", "    ")
@@ -259,7 +552,7 @@ func (client *Client) CreateHost(host Host) (*Host, error) {
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to create host record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to create host record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -302,11 +595,11 @@ func (client *Client) DeleteHost(host_id int) error {
 	return nil
 }
 
-func (client *Client) UpdateHost(host Host, host_id int) (*Host, error) {
+func (client *Client) UpdateHost(kv map[string]interface{}, host_id int) (*Host, error) {
 
-	reqBody, err := json.MarshalIndent(host, "", "    ")
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
 	if err != nil {
-		return nil, fmt.Errorf("[ERROR] Converting host record to json object: %v", err)
+		return nil, fmt.Errorf("[ERROR] Converting host key/value pair to json object: %v", err)
 	}
 	apiresult, resp, err := client.apiCall("PUT", "/hosts/"+strconv.Itoa(host_id)+"?approved=true", reqBody)
 	if err != nil {
@@ -324,7 +617,7 @@ func (client *Client) UpdateHost(host Host, host_id int) (*Host, error) {
 		if err != nil {
 			return nil, fmt.Errorf("[ERROR] %v", err)
 		}
-		return nil, fmt.Errorf("[ERROR] to update host record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] to update host record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -349,7 +642,7 @@ func (client *Client) CreatePool(pool Pool) (*Pool, error) {
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to create pool record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to create pool record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -412,7 +705,7 @@ func (client *Client) UpdatePool(kv map[string]interface{}, pool_id int) (*Pool,
 		if err != nil {
 			return nil, fmt.Errorf("[ERROR] %v", err)
 		}
-		return nil, fmt.Errorf("[ERROR] to update pool record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] to update pool record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -462,7 +755,7 @@ func (client *Client) CreateVolume(volume Volume) (*Volume, error) {
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to create volume record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to create volume record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -527,7 +820,7 @@ func (client *Client) UpdateVolume(kv map[string]interface{}, volume_id int) (*V
 		if err != nil {
 			return nil, fmt.Errorf("[ERROR] %v", err)
 		}
-		return nil, fmt.Errorf("[ERROR] to update volume record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] to update volume record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -550,7 +843,7 @@ func (client *Client) MoveVolume(kv map[string]interface{}, volume_id int) (*Vol
 		return &myvolume, nil
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] to move volume %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] to move volume %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -587,7 +880,7 @@ func (client *Client) LunMap(lun Lun) (*Lun, error) {
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to create lun record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to create lun record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -685,7 +978,7 @@ func (client *Client) CreateHostCluster(hostCluster Host_cluster) (*Host_cluster
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to create host cluster record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to create host cluster record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -750,7 +1043,7 @@ func (client *Client) UpdateHostCluster(kv map[string]interface{}, host_cluster_
 		if err != nil {
 			return nil, fmt.Errorf("[ERROR] %v", err)
 		}
-		return nil, fmt.Errorf("[ERROR] to update host_cluster record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] to update host_cluster record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
@@ -831,13 +1124,13 @@ func (client *Client) CreatePort(port Port, host_id int) (*Port, error) {
 
 	} else {
 		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
-		return nil, fmt.Errorf("[ERROR] failed to add port record: %v\n API response: %v", string(reqBody), string(out))
+		return nil, fmt.Errorf("[ERROR] failed to add port record: %v\n API response: %v", redactBody(reqBody), string(out))
 	}
 }
 
 func (client *Client) ReadPort(host_id int, port_address string) (*Port, error) {
 
-	apiresult, resp, err := client.apiCall("GET", "/hosts/"+strconv.Itoa(host_id)+"/ports/?address=eq:"+port_address, nil)
+	apiresult, resp, err := client.apiCall("GET", "/hosts/"+strconv.Itoa(host_id)+"/ports/?address=eq:"+url.QueryEscape(port_address), nil)
 	if err != nil {
 		return nil, fmt.Errorf("[ERROR] %v", err)
 	}
@@ -859,7 +1152,7 @@ func (client *Client) ReadPort(host_id int, port_address string) (*Port, error)
 
 func (client *Client) DeletePort(host_id int, port Port) (*Port, error) {
 
-	apiresult, resp, err := client.apiCall("DELETE", "/hosts/"+strconv.Itoa(host_id)+"/ports/"+port.Type+"/"+port.Address+"?approved=true", nil)
+	apiresult, resp, err := client.apiCall("DELETE", "/hosts/"+strconv.Itoa(host_id)+"/ports/"+port.Type+"/"+url.PathEscape(port.Address)+"?approved=true", nil)
 	if err != nil {
 		return nil, fmt.Errorf("[ERROR] %v", err)
 	}
@@ -878,3 +1171,1357 @@ func (client *Client) DeletePort(host_id int, port Port) (*Port, error) {
 		return nil, fmt.Errorf("[ERROR] %v", string(out))
 	}
 }
+
+func (client *Client) ReadMetadata(object_id int) (map[string]string, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/metadata/"+strconv.Itoa(object_id)+"?page_size=1000", nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mymetadata []Metadata
+		json.Unmarshal(*apiresult.Result, &mymetadata)
+		kv := make(map[string]string, len(mymetadata))
+		for _, entry := range mymetadata {
+			kv[entry.Key] = entry.Value
+		}
+		log.Printf("[INFO] succesfully fetched %v metadata keys of object id: %v", len(kv), object_id)
+		return kv, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the object with id: %v doesn't exists", object_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) SetMetadata(object_id int, kv map[string]string) error {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return fmt.Errorf("[ERROR] Converting metadata key/value pairs to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/metadata/"+strconv.Itoa(object_id), reqBody)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 || resp.StatusCode == 201 {
+		log.Printf("[INFO] Succesfully set metadata of object id: %v to: %v", object_id, redactBody(reqBody))
+		return nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] failed to set metadata of object id: %v: %v\n API response: %v", object_id, redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteMetadata(object_id int, key string) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/metadata/"+strconv.Itoa(object_id)+"/"+url.PathEscape(key)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted metadata key: %v of object id: %v", key, object_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The metadata key: %v of object id: %v doesn't exists", key, object_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) ListInitiators() ([]Initiator, error) {
+
+	pages, err := client.apiGetAll("/initiators?sort=address")
+	if err != nil {
+		return nil, err
+	}
+
+	var myinitiators []Initiator
+	for _, page := range pages {
+		var initiators []Initiator
+		if err := json.Unmarshal(page, &initiators); err != nil {
+			return nil, fmt.Errorf("[ERROR] %v", err)
+		}
+		myinitiators = append(myinitiators, initiators...)
+	}
+	log.Printf("[INFO] succesfully fetched %v initiators", len(myinitiators))
+	return myinitiators, nil
+}
+
+func (client *Client) CreateNetworkSpace(space Network_space) (*Network_space, error) {
+
+	reqBody, err := json.MarshalIndent(space, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting network space record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/network/spaces/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myspace Network_space
+		json.Unmarshal(*apiresult.Result, &myspace)
+		out, _ := json.MarshalIndent(myspace, "", "    ")
+		log.Printf("[INFO] Succesfully added new network space: %v\n", string(out))
+		return &myspace, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create network space record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadNetworkSpace(space_id int) (*Network_space, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/network/spaces/"+strconv.Itoa(space_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myspace Network_space
+		json.Unmarshal(*apiresult.Result, &myspace)
+		log.Printf("[INFO] succesfully fetched network space: %v", myspace.Name)
+		return &myspace, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the network space with id: %v doesn't exists", space_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateNetworkSpace(kv map[string]interface{}, space_id int) (*Network_space, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting network space key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/network/spaces/"+strconv.Itoa(space_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myspace Network_space
+		json.Unmarshal(*apiresult.Result, &myspace)
+		out, _ := json.MarshalIndent(myspace, "", "    ")
+		log.Printf("[INFO] Succesfully updated network space with id: %v to:\n %v", space_id, string(out))
+		return &myspace, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update network space record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteNetworkSpace(space_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/network/spaces/"+strconv.Itoa(space_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted network space with id: %v", space_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The network space with id: %v doesn't exists", space_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) AddNetworkSpaceIp(space_id int, ip_address string) error {
+
+	reqBody, err := json.MarshalIndent(Network_space_ip{Ip_address: ip_address}, "", "    ")
+	if err != nil {
+		return fmt.Errorf("[ERROR] Converting network space ip record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/network/spaces/"+strconv.Itoa(space_id)+"/ips", reqBody)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		log.Printf("[INFO] Succesfully added ip: %v to network space id: %v", ip_address, space_id)
+		return nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] failed to add ip: %v to network space id: %v\n API response: %v", ip_address, space_id, string(out))
+	}
+}
+
+// RemoveNetworkSpaceIp disables the ip address, the array only deletes disabled addresses
+func (client *Client) RemoveNetworkSpaceIp(space_id int, ip_address string) error {
+
+	ipUrl := "/network/spaces/" + strconv.Itoa(space_id) + "/ips/" + url.PathEscape(ip_address)
+
+	apiresult, resp, err := client.apiCall("POST", ipUrl+"/disable?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+	if resp.StatusCode == 404 {
+		log.Printf("[WARN] The ip: %v of network space id: %v doesn't exists", ip_address, space_id)
+		return nil
+	} else if resp.StatusCode != 200 {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] failed to disable ip: %v of network space id: %v\n API response: %v", ip_address, space_id, string(out))
+	}
+
+	apiresult, resp, err = client.apiCall("DELETE", ipUrl+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully removed ip: %v from network space id: %v", ip_address, space_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The ip: %v of network space id: %v doesn't exists", ip_address, space_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateNetworkInterface(networkInterface Network_interface) (*Network_interface, error) {
+
+	reqBody, err := json.MarshalIndent(networkInterface, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting network interface record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/network/interfaces/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myinterface Network_interface
+		json.Unmarshal(*apiresult.Result, &myinterface)
+		out, _ := json.MarshalIndent(myinterface, "", "    ")
+		log.Printf("[INFO] Succesfully added new network interface: %v\n", string(out))
+		return &myinterface, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create network interface record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadNetworkInterface(interface_id int) (*Network_interface, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/network/interfaces/"+strconv.Itoa(interface_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myinterface Network_interface
+		json.Unmarshal(*apiresult.Result, &myinterface)
+		log.Printf("[INFO] succesfully fetched network interface: %v", myinterface.Id)
+		return &myinterface, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the network interface with id: %v doesn't exists", interface_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateNetworkInterface(kv map[string]interface{}, interface_id int) (*Network_interface, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting network interface key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/network/interfaces/"+strconv.Itoa(interface_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myinterface Network_interface
+		json.Unmarshal(*apiresult.Result, &myinterface)
+		out, _ := json.MarshalIndent(myinterface, "", "    ")
+		log.Printf("[INFO] Succesfully updated network interface with id: %v to:\n %v", interface_id, string(out))
+		return &myinterface, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update network interface record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteNetworkInterface(interface_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/network/interfaces/"+strconv.Itoa(interface_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted network interface with id: %v", interface_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The network interface with id: %v doesn't exists", interface_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+// smbNotConfiguredCodes are the API error codes returned while the array is not joined to a domain
+var smbNotConfiguredCodes = []string{
+	"SMB_NOT_CONFIGURED",
+	"NOT_JOINED_TO_DOMAIN",
+	"ACTIVE_DIRECTORY_NOT_JOINED",
+}
+
+// isSmbNotConfigured recognizes the API errors returned while the array is not joined to a domain,
+// other domain errors e.g. an unknown account are reported as they are
+func isSmbNotConfigured(code string) bool {
+	for _, not_configured := range smbNotConfiguredCodes {
+		if code == not_configured {
+			return true
+		}
+	}
+	return false
+}
+
+// smbError explains API errors caused by the missing SMB domain join prerequisite
+func smbError(apiresult *ApiResult, message string) error {
+	out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+	if apiresult.Error != nil && isSmbNotConfigured(apiresult.Error.Code) {
+		return fmt.Errorf("[ERROR] %v\n SMB is not configured on the array, it must be joined to an Active Directory domain before SMB shares, users or groups can be created\n API response: %v", message, string(out))
+	}
+	return fmt.Errorf("[ERROR] %v\n API response: %v", message, string(out))
+}
+
+func (client *Client) CreateSmbShare(share Smb_share) (*Smb_share, error) {
+
+	reqBody, err := json.MarshalIndent(share, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb share record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/shares/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myshare Smb_share
+		json.Unmarshal(*apiresult.Result, &myshare)
+		out, _ := json.MarshalIndent(myshare, "", "    ")
+		log.Printf("[INFO] Succesfully added new smb share: %v\n", string(out))
+		return &myshare, nil
+
+	} else {
+		return nil, smbError(apiresult, fmt.Sprintf("failed to create smb share record: %v", redactBody(reqBody)))
+	}
+}
+
+func (client *Client) ReadSmbShare(share_id int) (*Smb_share, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/shares/"+strconv.Itoa(share_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myshare Smb_share
+		json.Unmarshal(*apiresult.Result, &myshare)
+		log.Printf("[INFO] succesfully fetched smb share: %v", share_id)
+		return &myshare, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the smb share with id: %v doesn't exists", share_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateSmbShare(kv map[string]interface{}, share_id int) (*Smb_share, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb share key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/shares/"+strconv.Itoa(share_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myshare Smb_share
+		json.Unmarshal(*apiresult.Result, &myshare)
+		out, _ := json.MarshalIndent(myshare, "", "    ")
+		log.Printf("[INFO] Succesfully updated smb share with id: %v to:\n %v", share_id, string(out))
+		return &myshare, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update smb share record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteSmbShare(share_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/shares/"+strconv.Itoa(share_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted smb share with id: %v", share_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The smb share with id: %v doesn't exists", share_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateSmbUser(user Smb_user) (*Smb_user, error) {
+
+	reqBody, err := json.MarshalIndent(user, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb user record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/smb_users/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myuser Smb_user
+		json.Unmarshal(*apiresult.Result, &myuser)
+		out, _ := json.MarshalIndent(myuser, "", "    ")
+		log.Printf("[INFO] Succesfully added new smb user: %v\n", string(out))
+		return &myuser, nil
+
+	} else {
+		return nil, smbError(apiresult, fmt.Sprintf("failed to create smb user record: %v", redactBody(reqBody)))
+	}
+}
+
+func (client *Client) ReadSmbUser(user_id int) (*Smb_user, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/smb_users/"+strconv.Itoa(user_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myuser Smb_user
+		json.Unmarshal(*apiresult.Result, &myuser)
+		log.Printf("[INFO] succesfully fetched smb user: %v", user_id)
+		return &myuser, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the smb user with id: %v doesn't exists", user_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateSmbUser(kv map[string]interface{}, user_id int) (*Smb_user, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb user key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/smb_users/"+strconv.Itoa(user_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myuser Smb_user
+		json.Unmarshal(*apiresult.Result, &myuser)
+		out, _ := json.MarshalIndent(myuser, "", "    ")
+		log.Printf("[INFO] Succesfully updated smb user with id: %v to:\n %v", user_id, string(out))
+		return &myuser, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update smb user record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteSmbUser(user_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/smb_users/"+strconv.Itoa(user_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted smb user with id: %v", user_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The smb user with id: %v doesn't exists", user_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateSmbGroup(group Smb_group) (*Smb_group, error) {
+
+	reqBody, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb group record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/smb_groups/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var mygroup Smb_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		out, _ := json.MarshalIndent(mygroup, "", "    ")
+		log.Printf("[INFO] Succesfully added new smb group: %v\n", string(out))
+		return &mygroup, nil
+
+	} else {
+		return nil, smbError(apiresult, fmt.Sprintf("failed to create smb group record: %v", redactBody(reqBody)))
+	}
+}
+
+func (client *Client) ReadSmbGroup(group_id int) (*Smb_group, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/smb_groups/"+strconv.Itoa(group_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mygroup Smb_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		log.Printf("[INFO] succesfully fetched smb group: %v", group_id)
+		return &mygroup, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the smb group with id: %v doesn't exists", group_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateSmbGroup(kv map[string]interface{}, group_id int) (*Smb_group, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting smb group key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/smb_groups/"+strconv.Itoa(group_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mygroup Smb_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		out, _ := json.MarshalIndent(mygroup, "", "    ")
+		log.Printf("[INFO] Succesfully updated smb group with id: %v to:\n %v", group_id, string(out))
+		return &mygroup, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update smb group record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteSmbGroup(group_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/smb_groups/"+strconv.Itoa(group_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted smb group with id: %v", group_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The smb group with id: %v doesn't exists", group_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateTreeq(filesystem_id int, treeq Treeq) (*Treeq, error) {
+
+	reqBody, err := json.MarshalIndent(treeq, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting treeq record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/filesystems/"+strconv.Itoa(filesystem_id)+"/treeqs/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var mytreeq Treeq
+		json.Unmarshal(*apiresult.Result, &mytreeq)
+		out, _ := json.MarshalIndent(mytreeq, "", "    ")
+		log.Printf("[INFO] Succesfully added new treeq: %v\n", string(out))
+		return &mytreeq, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create treeq record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadTreeq(filesystem_id int, treeq_id int) (*Treeq, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/filesystems/"+strconv.Itoa(filesystem_id)+"/treeqs/"+strconv.Itoa(treeq_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mytreeq Treeq
+		json.Unmarshal(*apiresult.Result, &mytreeq)
+		log.Printf("[INFO] succesfully fetched treeq: %v", treeq_id)
+		return &mytreeq, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the treeq with id: %v doesn't exists", treeq_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateTreeq(kv map[string]interface{}, filesystem_id int, treeq_id int) (*Treeq, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting treeq key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/filesystems/"+strconv.Itoa(filesystem_id)+"/treeqs/"+strconv.Itoa(treeq_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mytreeq Treeq
+		json.Unmarshal(*apiresult.Result, &mytreeq)
+		out, _ := json.MarshalIndent(mytreeq, "", "    ")
+		log.Printf("[INFO] Succesfully updated treeq with id: %v to:\n %v", treeq_id, string(out))
+		return &mytreeq, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update treeq record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteTreeq(filesystem_id int, treeq_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/filesystems/"+strconv.Itoa(filesystem_id)+"/treeqs/"+strconv.Itoa(treeq_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted treeq with id: %v", treeq_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The treeq with id: %v doesn't exists", treeq_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+// nfsQuotaPath returns the collection of the user or group quotas of a filesystem, kind is user or group
+func nfsQuotaPath(kind string, filesystem_id int) string {
+	return "/filesystems/" + strconv.Itoa(filesystem_id) + "/" + kind + "_quotas"
+}
+
+func (client *Client) CreateNfsQuota(kind string, filesystem_id int, quota Nfs_quota) (*Nfs_quota, error) {
+
+	reqBody, err := json.MarshalIndent(quota, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting nfs quota record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", nfsQuotaPath(kind, filesystem_id)+"/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myquota Nfs_quota
+		json.Unmarshal(*apiresult.Result, &myquota)
+		out, _ := json.MarshalIndent(myquota, "", "    ")
+		log.Printf("[INFO] Succesfully added new nfs quota: %v\n", string(out))
+		return &myquota, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create nfs quota record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadNfsQuota(kind string, filesystem_id int, quota_id int) (*Nfs_quota, error) {
+
+	apiresult, resp, err := client.apiCall("GET", nfsQuotaPath(kind, filesystem_id)+"/"+strconv.Itoa(quota_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myquota Nfs_quota
+		json.Unmarshal(*apiresult.Result, &myquota)
+		log.Printf("[INFO] succesfully fetched nfs quota: %v", quota_id)
+		return &myquota, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the nfs quota with id: %v doesn't exists", quota_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateNfsQuota(kv map[string]interface{}, kind string, filesystem_id int, quota_id int) (*Nfs_quota, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting nfs quota key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", nfsQuotaPath(kind, filesystem_id)+"/"+strconv.Itoa(quota_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myquota Nfs_quota
+		json.Unmarshal(*apiresult.Result, &myquota)
+		out, _ := json.MarshalIndent(myquota, "", "    ")
+		log.Printf("[INFO] Succesfully updated nfs quota with id: %v to:\n %v", quota_id, string(out))
+		return &myquota, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update nfs quota record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteNfsQuota(kind string, filesystem_id int, quota_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", nfsQuotaPath(kind, filesystem_id)+"/"+strconv.Itoa(quota_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted nfs quota with id: %v", quota_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The nfs quota with id: %v doesn't exists", quota_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) ListNfsQuotas(kind string, filesystem_id int) ([]Nfs_quota, error) {
+
+	pages, err := client.apiGetAll(nfsQuotaPath(kind, filesystem_id) + "?sort=id")
+	if err != nil {
+		return nil, err
+	}
+
+	var myquotas []Nfs_quota
+	for _, page := range pages {
+		var quotas []Nfs_quota
+		if err := json.Unmarshal(page, &quotas); err != nil {
+			return nil, fmt.Errorf("[ERROR] %v", err)
+		}
+		myquotas = append(myquotas, quotas...)
+	}
+	log.Printf("[INFO] succesfully fetched %v nfs %v quotas of filesystem: %v", len(myquotas), kind, filesystem_id)
+	return myquotas, nil
+}
+
+func (client *Client) CreateUser(user User) (*User, error) {
+
+	reqBody, err := json.MarshalIndent(user, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting user record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/users/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myuser User
+		json.Unmarshal(*apiresult.Result, &myuser)
+		out, _ := json.MarshalIndent(myuser, "", "    ")
+		log.Printf("[INFO] Succesfully added new user: %v\n", string(out))
+		return &myuser, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create user record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadUser(user_id int) (*User, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/users/"+strconv.Itoa(user_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myuser User
+		json.Unmarshal(*apiresult.Result, &myuser)
+		log.Printf("[INFO] succesfully fetched user: %v", user_id)
+		return &myuser, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the user with id: %v doesn't exists", user_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateUser(kv map[string]interface{}, user_id int) (*User, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting user key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/users/"+strconv.Itoa(user_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myuser User
+		json.Unmarshal(*apiresult.Result, &myuser)
+		out, _ := json.MarshalIndent(myuser, "", "    ")
+		log.Printf("[INFO] Succesfully updated user with id: %v to:\n %v", user_id, string(out))
+		return &myuser, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update user record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteUser(user_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/users/"+strconv.Itoa(user_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted user with id: %v", user_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The user with id: %v doesn't exists", user_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateLdapConfig(config Ldap_config) (*Ldap_config, error) {
+
+	reqBody, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting ldap config record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/config/ldap/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myconfig Ldap_config
+		json.Unmarshal(*apiresult.Result, &myconfig)
+		out, _ := json.MarshalIndent(myconfig, "", "    ")
+		log.Printf("[INFO] Succesfully added new ldap config: %v\n", string(out))
+		return &myconfig, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create ldap config record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadLdapConfig(config_id int) (*Ldap_config, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/config/ldap/"+strconv.Itoa(config_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myconfig Ldap_config
+		json.Unmarshal(*apiresult.Result, &myconfig)
+		log.Printf("[INFO] succesfully fetched ldap config: %v", config_id)
+		return &myconfig, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the ldap config with id: %v doesn't exists", config_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateLdapConfig(kv map[string]interface{}, config_id int) (*Ldap_config, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting ldap config key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/config/ldap/"+strconv.Itoa(config_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myconfig Ldap_config
+		json.Unmarshal(*apiresult.Result, &myconfig)
+		out, _ := json.MarshalIndent(myconfig, "", "    ")
+		log.Printf("[INFO] Succesfully updated ldap config with id: %v to:\n %v", config_id, string(out))
+		return &myconfig, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update ldap config record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteLdapConfig(config_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/config/ldap/"+strconv.Itoa(config_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted ldap config with id: %v", config_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The ldap config with id: %v doesn't exists", config_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+// TestLdapConfig asks the array to connect and bind to the configured directory servers
+func (client *Client) TestLdapConfig(config_id int) error {
+
+	apiresult, resp, err := client.apiCall("POST", "/config/ldap/"+strconv.Itoa(config_id)+"/test", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully tested the connection of ldap config id: %v", config_id)
+		return nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] connection test of ldap config id: %v failed\n API response: %v", config_id, string(out))
+	}
+}
+
+func (client *Client) CreateLdapGroup(group Ldap_group) (*Ldap_group, error) {
+
+	reqBody, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting ldap group record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/users/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var mygroup Ldap_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		out, _ := json.MarshalIndent(mygroup, "", "    ")
+		log.Printf("[INFO] Succesfully added new ldap group: %v\n", string(out))
+		return &mygroup, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create ldap group record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadLdapGroup(group_id int) (*Ldap_group, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/users/"+strconv.Itoa(group_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mygroup Ldap_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		log.Printf("[INFO] succesfully fetched ldap group: %v", group_id)
+		return &mygroup, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the ldap group with id: %v doesn't exists", group_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateLdapGroup(kv map[string]interface{}, group_id int) (*Ldap_group, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting ldap group key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/users/"+strconv.Itoa(group_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mygroup Ldap_group
+		json.Unmarshal(*apiresult.Result, &mygroup)
+		out, _ := json.MarshalIndent(mygroup, "", "    ")
+		log.Printf("[INFO] Succesfully updated ldap group with id: %v to:\n %v", group_id, string(out))
+		return &mygroup, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update ldap group record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteLdapGroup(group_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/users/"+strconv.Itoa(group_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted ldap group with id: %v", group_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The ldap group with id: %v doesn't exists", group_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) ListUserPools(user_id int) ([]int, error) {
+
+	pages, err := client.apiGetAll("/users/" + strconv.Itoa(user_id) + "/pools?sort=id")
+	if err != nil {
+		return nil, err
+	}
+
+	pool_ids := make([]int, 0)
+	for _, page := range pages {
+		var pools []Pool
+		if err := json.Unmarshal(page, &pools); err != nil {
+			return nil, fmt.Errorf("[ERROR] %v", err)
+		}
+		for _, pool := range pools {
+			pool_ids = append(pool_ids, pool.Id)
+		}
+	}
+	log.Printf("[INFO] succesfully fetched %v pools of user id: %v", len(pool_ids), user_id)
+	return pool_ids, nil
+}
+
+func (client *Client) AddPoolOwner(pool_id int, user_id int) error {
+
+	reqBody, err := json.MarshalIndent(map[string]int{"user_id": user_id}, "", "    ")
+	if err != nil {
+		return fmt.Errorf("[ERROR] Converting pool owner record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/pools/"+strconv.Itoa(pool_id)+"/owners", reqBody)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		log.Printf("[INFO] Succesfully added owner user id: %v to pool id: %v", user_id, pool_id)
+		return nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] failed to add owner user id: %v to pool id: %v\n API response: %v", user_id, pool_id, string(out))
+	}
+}
+
+func (client *Client) RemovePoolOwner(pool_id int, user_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/pools/"+strconv.Itoa(pool_id)+"/owners/"+strconv.Itoa(user_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully removed owner user id: %v from pool id: %v", user_id, pool_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The user id: %v is not an owner of pool id: %v", user_id, pool_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateNotificationTarget(target Notification_target) (*Notification_target, error) {
+
+	reqBody, err := json.MarshalIndent(target, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting notification target record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/notifications/targets/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var mytarget Notification_target
+		json.Unmarshal(*apiresult.Result, &mytarget)
+		out, _ := json.MarshalIndent(mytarget, "", "    ")
+		log.Printf("[INFO] Succesfully added new notification target: %v\n", string(out))
+		return &mytarget, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create notification target record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadNotificationTarget(target_id int) (*Notification_target, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/notifications/targets/"+strconv.Itoa(target_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mytarget Notification_target
+		json.Unmarshal(*apiresult.Result, &mytarget)
+		log.Printf("[INFO] succesfully fetched notification target: %v", target_id)
+		return &mytarget, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the notification target with id: %v doesn't exists", target_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateNotificationTarget(kv map[string]interface{}, target_id int) (*Notification_target, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting notification target key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/notifications/targets/"+strconv.Itoa(target_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mytarget Notification_target
+		json.Unmarshal(*apiresult.Result, &mytarget)
+		out, _ := json.MarshalIndent(mytarget, "", "    ")
+		log.Printf("[INFO] Succesfully updated notification target with id: %v to:\n %v", target_id, string(out))
+		return &mytarget, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update notification target record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteNotificationTarget(target_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/notifications/targets/"+strconv.Itoa(target_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted notification target with id: %v", target_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The notification target with id: %v doesn't exists", target_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) CreateNotificationRule(rule Notification_rule) (*Notification_rule, error) {
+
+	reqBody, err := json.MarshalIndent(rule, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting notification rule record to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("POST", "/notifications/rules/", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 201 {
+		var myrule Notification_rule
+		json.Unmarshal(*apiresult.Result, &myrule)
+		out, _ := json.MarshalIndent(myrule, "", "    ")
+		log.Printf("[INFO] Succesfully added new notification rule: %v\n", string(out))
+		return &myrule, nil
+
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] failed to create notification rule record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) ReadNotificationRule(rule_id int) (*Notification_rule, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/notifications/rules/"+strconv.Itoa(rule_id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myrule Notification_rule
+		json.Unmarshal(*apiresult.Result, &myrule)
+		log.Printf("[INFO] succesfully fetched notification rule: %v", rule_id)
+		return &myrule, nil
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] the notification rule with id: %v doesn't exists", rule_id)
+		return nil, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+func (client *Client) UpdateNotificationRule(kv map[string]interface{}, rule_id int) (*Notification_rule, error) {
+
+	reqBody, err := json.MarshalIndent(kv, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Converting notification rule key/value pair to json object: %v", err)
+	}
+	apiresult, resp, err := client.apiCall("PUT", "/notifications/rules/"+strconv.Itoa(rule_id)+"?approved=true", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myrule Notification_rule
+		json.Unmarshal(*apiresult.Result, &myrule)
+		out, _ := json.MarshalIndent(myrule, "", "    ")
+		log.Printf("[INFO] Succesfully updated notification rule with id: %v to:\n %v", rule_id, string(out))
+		return &myrule, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] to update notification rule record: %v\n API response: %v", redactBody(reqBody), string(out))
+	}
+}
+
+func (client *Client) DeleteNotificationRule(rule_id int) error {
+
+	apiresult, resp, err := client.apiCall("DELETE", "/notifications/rules/"+strconv.Itoa(rule_id)+"?approved=true", nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		log.Printf("[INFO] Succesfully deleted notification rule with id: %v", rule_id)
+	} else if resp.StatusCode == 404 {
+		log.Printf("[WARN] The notification rule with id: %v doesn't exists", rule_id)
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return fmt.Errorf("[ERROR] %v", string(out))
+	}
+	return nil
+}
+
+func (client *Client) ReadSystem() (*System, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/system", nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var mysystem System
+		json.Unmarshal(*apiresult.Result, &mysystem)
+		log.Printf("[INFO] succesfully fetched system: %v", mysystem.Name)
+		return &mysystem, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return nil, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
+
+// ListEvents returns the events matching the filters, each filter is a field=operator:value query parameter
+func (client *Client) ListEvents(filters []string) ([]Event, error) {
+
+	query := "/events?sort=id"
+	for _, filter := range filters {
+		query += "&" + filter
+	}
+	pages, err := client.apiGetAll(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var myevents []Event
+	for _, page := range pages {
+		var events []Event
+		if err := json.Unmarshal(page, &events); err != nil {
+			return nil, fmt.Errorf("[ERROR] %v", err)
+		}
+		myevents = append(myevents, events...)
+	}
+	log.Printf("[INFO] succesfully fetched %v events", len(myevents))
+	return myevents, nil
+}
+
+// LastEventId returns the id of the newest event of the array
+func (client *Client) LastEventId() (int, error) {
+
+	apiresult, resp, err := client.apiCall("GET", "/events?sort=-id&page_size=1", nil)
+	if err != nil {
+		return 0, fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if resp.StatusCode == 200 {
+		var myevents []Event
+		json.Unmarshal(*apiresult.Result, &myevents)
+		if len(myevents) == 0 {
+			return 0, nil
+		}
+		return myevents[0].Id, nil
+	} else {
+		out, _ := json.MarshalIndent(apiresult.Error, "", "    ")
+		return 0, fmt.Errorf("[ERROR] %v", string(out))
+	}
+}
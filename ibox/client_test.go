@@ -0,0 +1,35 @@
+package ibox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsSmbNotConfigured(t *testing.T) {
+	cases := map[string]bool{
+		"SMB_NOT_CONFIGURED":          true,
+		"NOT_JOINED_TO_DOMAIN":        true,
+		"ACTIVE_DIRECTORY_NOT_JOINED": true,
+		"DOMAIN_USER_NOT_FOUND":       false,
+		"INVALID_DOMAIN_NAME":         false,
+		"SMB_NOT_CONFIGURED_YET":      false,
+		"smb_not_configured":          false,
+		"":                            false,
+	}
+	for code, expected := range cases {
+		if got := isSmbNotConfigured(code); got != expected {
+			t.Errorf("isSmbNotConfigured(%q) = %v, expected %v", code, got, expected)
+		}
+	}
+}
+
+func TestSmbError(t *testing.T) {
+	err := smbError(&ApiResult{Error: &ApiError{Code: "NOT_JOINED_TO_DOMAIN"}}, "failed")
+	if !strings.Contains(err.Error(), "SMB is not configured") {
+		t.Errorf("expected the domain join hint, got: %v", err)
+	}
+	err = smbError(&ApiResult{Error: &ApiError{Code: "DOMAIN_USER_NOT_FOUND"}}, "failed")
+	if strings.Contains(err.Error(), "SMB is not configured") {
+		t.Errorf("unexpected domain join hint, got: %v", err)
+	}
+}
@@ -2,22 +2,194 @@ package ibox
 
 import (
 	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
 	Username string
 	Password string
 	Hostname string
+
+	RequestsPerSecond     float64
+	Burst                 int
+	MaxConcurrentRequests int
+
+	LogHttpBodies  bool
+	RecordEventIds bool
+
+	Profile          string
+	CredentialsFile  string
+	CredentialHelper string
+
+	Systems []SystemConfig
+
+	DefaultMetadata map[string]string
+}
+
+// SystemConfig describes one of the InfiniBox systems managed by the provider
+type SystemConfig struct {
+	Name     string
+	Hostname string
+	Username string
+	Password string
+	Profile  string
+}
+
+// ClientPool is the provider meta, it keeps one client per configured system
+type ClientPool struct {
+	Default *Client
+	Systems map[string]*Client
 }
 
 func (c *Config) Client() (*Client, error) {
-	client, err := NewClient(c.Username, c.Password, c.Hostname)
+	return c.newClient(c.Username, c.Password, c.Hostname)
+}
+
+func (c *Config) newClient(username string, password string, hostname string) (*Client, error) {
+	client, err := NewClient(username, password, hostname)
 
 	if err != nil {
 		return nil, fmt.Errorf("[ERROR] setting up client failed: %s", err)
 	}
 
-	fmt.Printf("[INFO] Client configured for server %s", c.Hostname)
+	client.SetThrottle(c.RequestsPerSecond, c.Burst, c.MaxConcurrentRequests)
+	client.LogHttpBodies = c.LogHttpBodies
+	client.RecordEventIds = c.RecordEventIds
+	client.DefaultMetadata = c.DefaultMetadata
+	client.detectVersion()
+
+	fmt.Printf("[INFO] Client configured for server %s", hostname)
+
+	return client, nil
+}
+
+// Clients sets up the default client and a client for each system block
+func (c *Config) Clients() (*ClientPool, error) {
+	if err := c.resolveCredentials(); err != nil {
+		return nil, err
+	}
+
+	pool := ClientPool{
+		Systems: make(map[string]*Client),
+	}
+
+	if c.Hostname != "" {
+		if c.Username == "" || c.Password == "" {
+			return nil, fmt.Errorf("[ERROR] username and password are required for hostname: %v", c.Hostname)
+		}
+		client, err := c.Client()
+		if err != nil {
+			return nil, err
+		}
+		pool.Default = client
+	}
+
+	for _, system := range c.Systems {
+		if _, ok := pool.Systems[system.Name]; ok {
+			return nil, fmt.Errorf("[ERROR] system: %v is configured more than once", system.Name)
+		}
+		if system.Hostname == "" || system.Username == "" || system.Password == "" {
+			return nil, fmt.Errorf("[ERROR] hostname, username and password are required for system: %v", system.Name)
+		}
+		client, err := c.newClient(system.Username, system.Password, system.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		pool.Systems[system.Name] = client
+	}
+
+	if pool.Default == nil && len(pool.Systems) == 0 {
+		return nil, fmt.Errorf("[ERROR] either hostname or at least one system block must be configured")
+	}
+	return &pool, nil
+}
 
+// Get returns the client of the named system, an empty name selects the default one
+func (p *ClientPool) Get(system string) (*Client, error) {
+	if system == "" {
+		if p.Default != nil {
+			return p.Default, nil
+		}
+		if len(p.Systems) == 1 {
+			for _, client := range p.Systems {
+				return client, nil
+			}
+		}
+		return nil, fmt.Errorf("[ERROR] the system argument is required, the provider has no default hostname")
+	}
+	client, ok := p.Systems[system]
+	if !ok {
+		return nil, fmt.Errorf("[ERROR] system: %v is not configured in the provider", system)
+	}
 	return client, nil
 }
+
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// getClient returns the client for the system the resource belongs to
+func getClient(d resourceGetter, meta interface{}) (*Client, error) {
+	return meta.(*ClientPool).Get(d.Get("system").(string))
+}
+
+// importSystem splits the optional <system>: prefix off the import id and sets the system argument,
+// so the object is read from that system instead of the default one. The prefix ends at the first
+// colon before any slash, colons of IQNs and WWNs in composite ids are left alone.
+func importSystem(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	if i := strings.Index(id, ":"); i >= 0 && !strings.Contains(id[:i], "/") {
+		if i == 0 || i == len(id)-1 {
+			return fmt.Errorf("[ERROR] import id: %v is not in the <system>:<id> format", id)
+		}
+		d.Set("system", id[:i])
+		d.SetId(id[i+1:])
+	}
+	_, err := getClient(d, meta)
+	return err
+}
+
+// importStatePassthrough imports objects by their id, optionally prefixed with the system name
+func importStatePassthrough(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := importSystem(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// importFilesystemChild imports objects whose id is only unique within their filesystem, e.g. treeqs
+// and quotas, with the filesystem_id/<idName> id
+func importFilesystemChild(idName string) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if err := importSystem(d, meta); err != nil {
+			return nil, err
+		}
+		parts := strings.SplitN(d.Id(), "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("[ERROR] import id: %v is not in the filesystem_id/%v format", d.Id(), idName)
+		}
+		filesystem_id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] import id: %v has an invalid filesystem_id: %v", d.Id(), err)
+		}
+		if _, err := strconv.Atoi(parts[1]); err != nil {
+			return nil, fmt.Errorf("[ERROR] import id: %v has an invalid %v: %v", d.Id(), idName, err)
+		}
+		d.Set("filesystem_id", filesystem_id)
+		d.SetId(parts[1])
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+func systemSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Name of the provider system block to manage the object on, defaults to the provider hostname",
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+	}
+}
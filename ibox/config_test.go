@@ -0,0 +1,112 @@
+package ibox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestImportSystem(t *testing.T) {
+	pool := &ClientPool{
+		Default: &Client{},
+		Systems: map[string]*Client{"prod-b": &Client{}},
+	}
+	cases := []struct {
+		name     string
+		resource *schema.Resource
+		id       string
+		system   string
+		expected string
+		fails    bool
+	}{
+		{"plain id", resourceIboxUser(), "12", "", "12", false},
+		{"system prefix", resourceIboxUser(), "prod-b:12", "prod-b", "12", false},
+		{"unknown system", resourceIboxUser(), "prod-c:12", "", "", true},
+		{"empty system", resourceIboxUser(), ":12", "", "", true},
+		{"empty id", resourceIboxUser(), "prod-b:", "", "", true},
+		{"filesystem child", resourceIboxTreeq(), "prod-b:3/4", "prod-b", "4", false},
+		{"iqn port without system", resourceIboxHostPort(), "1234/ISCSI/iqn.1994-05.com.redhat:1a2b3c", "", "1234/ISCSI/iqn.1994-05.com.redhat:1a2b3c", false},
+		{"wwn port with system", resourceIboxHostPort(), "prod-b:1234/FC/21:10:00:24:ff:91:3b:ff", "prod-b", "1234/FC/21:10:00:24:ff:91:3b:ff", false},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, c.resource.Schema, map[string]interface{}{})
+		d.SetId(c.id)
+		result, err := c.resource.Importer.State(d, pool)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%v: expected an error for import id %q", c.name, c.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error for import id %q: %v", c.name, c.id, err)
+			continue
+		}
+		if got := result[0].Get("system").(string); got != c.system {
+			t.Errorf("%v: system = %q, expected %q", c.name, got, c.system)
+		}
+		if got := result[0].Id(); got != c.expected {
+			t.Errorf("%v: id = %q, expected %q", c.name, got, c.expected)
+		}
+	}
+}
+
+func TestImportSystemResolvesClient(t *testing.T) {
+	client_a := &Client{}
+	client_b := &Client{}
+	pool := &ClientPool{
+		Default: client_a,
+		Systems: map[string]*Client{"prod-b": client_b},
+	}
+	d := schema.TestResourceDataRaw(t, resourceIboxSmbUser().Schema, map[string]interface{}{})
+	d.SetId("prod-b:7")
+	if _, err := importStatePassthrough(d, pool); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client, err := getClient(d, pool)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client != client_b {
+		t.Errorf("the imported object is read through the default client instead of prod-b")
+	}
+}
+
+func TestImportFilesystemChild(t *testing.T) {
+	pool := &ClientPool{Default: &Client{}}
+	cases := []struct {
+		name          string
+		resource      *schema.Resource
+		id            string
+		filesystem_id int
+		expected      string
+		fails         bool
+	}{
+		{"treeq", resourceIboxTreeq(), "3/4", 3, "4", false},
+		{"quota", resourceIboxNfsQuota("user"), "3/1001", 3, "1001", false},
+		{"missing filesystem_id", resourceIboxTreeq(), "4", 0, "", true},
+		{"invalid filesystem_id", resourceIboxTreeq(), "fs/4", 0, "", true},
+		{"invalid quota_id", resourceIboxNfsQuota("group"), "3/q", 0, "", true},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, c.resource.Schema, map[string]interface{}{})
+		d.SetId(c.id)
+		result, err := c.resource.Importer.State(d, pool)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%v: expected an error for import id %q", c.name, c.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error for import id %q: %v", c.name, c.id, err)
+			continue
+		}
+		if got := result[0].Get("filesystem_id").(int); got != c.filesystem_id {
+			t.Errorf("%v: filesystem_id = %v, expected %v", c.name, got, c.filesystem_id)
+		}
+		if got := result[0].Id(); got != c.expected {
+			t.Errorf("%v: id = %q, expected %q", c.name, got, c.expected)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package ibox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-ini/ini"
+	"github.com/mitchellh/go-homedir"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+const (
+	defaultCredentialsFile = "~/.ibox/credentials"
+	defaultProfile         = "default"
+)
+
+// credentials returned by the credential helper on its standard output
+type helperCredentials struct {
+	Hostname string `json:"hostname"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// loadProfile reads the profile section of the INI credentials file.
+// A missing file is only an error if the profile was explicitly requested.
+func loadProfile(path string, profile string, required bool) (*SystemConfig, error) {
+	if path == "" {
+		path = defaultCredentialsFile
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] expanding credentials file path %v: %v", path, err)
+	}
+	if _, err := os.Stat(expanded); os.IsNotExist(err) {
+		if required {
+			return nil, fmt.Errorf("[ERROR] credentials file %v does not exist, it is needed for profile: %v", expanded, profile)
+		}
+		return nil, nil
+	}
+
+	file, err := ini.Load(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] parsing credentials file %v: %v", expanded, err)
+	}
+	section, err := file.GetSection(profile)
+	if err != nil {
+		if required {
+			return nil, fmt.Errorf("[ERROR] profile %v not found in credentials file %v", profile, expanded)
+		}
+		return nil, nil
+	}
+
+	log.Printf("[INFO] Using profile %v from credentials file %v", profile, expanded)
+	return &SystemConfig{
+		Hostname: section.Key("hostname").String(),
+		Username: section.Key("username").String(),
+		Password: section.Key("password").String(),
+	}, nil
+}
+
+// runCredentialHelper executes the helper command and decodes the JSON it prints.
+// The system name and hostname are passed to the helper as environment variables.
+func runCredentialHelper(command string, system string, hostname string) (*helperCredentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), "IBOX_SYSTEM="+system, "IBOX_HOSTNAME="+hostname)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("[ERROR] credential helper failed: %v: %v", err, stderr.String())
+	}
+
+	var creds helperCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return nil, fmt.Errorf("[ERROR] credential helper output is not valid JSON: %v", err)
+	}
+	return &creds, nil
+}
+
+// mergeCredentials fills the empty fields of the system from the given values
+func mergeCredentials(system *SystemConfig, hostname string, username string, password string) {
+	if system.Hostname == "" {
+		system.Hostname = hostname
+	}
+	if system.Username == "" {
+		system.Username = username
+	}
+	if system.Password == "" {
+		system.Password = password
+	}
+}
+
+func (s *SystemConfig) complete() bool {
+	return s.Hostname != "" && s.Username != "" && s.Password != ""
+}
+
+// resolve completes the system from its profile and the credential helper
+func (s *SystemConfig) resolve(credentialsFile string, helper string) error {
+	if s.Profile != "" {
+		profile, err := loadProfile(credentialsFile, s.Profile, true)
+		if err != nil {
+			return err
+		}
+		mergeCredentials(s, profile.Hostname, profile.Username, profile.Password)
+	}
+	if !s.complete() && helper != "" && s.Hostname != "" {
+		creds, err := runCredentialHelper(helper, s.Name, s.Hostname)
+		if err != nil {
+			return err
+		}
+		mergeCredentials(s, creds.Hostname, creds.Username, creds.Password)
+	}
+	return nil
+}
+
+// resolveCredentials fills the credentials which are not set in the configuration or the
+// environment, first from the credential helper and then from the credentials file profile.
+// System blocks fall back to their own profile, the helper and finally the provider credentials.
+func (c *Config) resolveCredentials() error {
+	provider := SystemConfig{
+		Hostname: c.Hostname,
+		Username: c.Username,
+		Password: c.Password,
+	}
+
+	if !provider.complete() && c.CredentialHelper != "" && (provider.Hostname != "" || len(c.Systems) == 0) {
+		creds, err := runCredentialHelper(c.CredentialHelper, "", provider.Hostname)
+		if err != nil {
+			return err
+		}
+		mergeCredentials(&provider, creds.Hostname, creds.Username, creds.Password)
+	}
+
+	if !provider.complete() {
+		profile, err := loadProfile(c.CredentialsFile, c.Profile, c.Profile != "")
+		if err != nil {
+			return err
+		}
+		if profile != nil {
+			mergeCredentials(&provider, profile.Hostname, profile.Username, profile.Password)
+		}
+	}
+
+	c.Hostname = provider.Hostname
+	c.Username = provider.Username
+	c.Password = provider.Password
+
+	for i := range c.Systems {
+		system := &c.Systems[i]
+		if err := system.resolve(c.CredentialsFile, c.CredentialHelper); err != nil {
+			return err
+		}
+		mergeCredentials(system, "", c.Username, c.Password)
+	}
+	return nil
+}
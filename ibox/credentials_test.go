@@ -0,0 +1,186 @@
+package ibox
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testCredentialsFile = `[default]
+hostname = ibox-profile
+username = profile-user
+password = profile-pass
+
+[lab]
+hostname = ibox-lab
+username = lab-user
+password = lab-pass
+`
+
+func testCredentialsPath(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential helpers of the tests are shell commands")
+	}
+	dir, err := ioutil.TempDir("", "ibox-credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(path, []byte(testCredentialsFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveCredentialsPrecedence(t *testing.T) {
+	path := testCredentialsPath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	helper := `echo '{"username": "helper-user", "password": "helper-pass"}'`
+	cases := []struct {
+		name     string
+		config   Config
+		expected SystemConfig
+	}{
+		{
+			"explicit arguments win",
+			Config{Hostname: "ibox-arg", Username: "arg-user", Password: "arg-pass", CredentialHelper: helper, CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-arg", Username: "arg-user", Password: "arg-pass"},
+		},
+		{
+			"helper before profile",
+			Config{Hostname: "ibox-arg", CredentialHelper: helper, CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-arg", Username: "helper-user", Password: "helper-pass"},
+		},
+		{
+			"helper only fills missing fields",
+			Config{Hostname: "ibox-arg", Username: "arg-user", CredentialHelper: helper, CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-arg", Username: "arg-user", Password: "helper-pass"},
+		},
+		{
+			"default profile",
+			Config{CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-profile", Username: "profile-user", Password: "profile-pass"},
+		},
+		{
+			"named profile",
+			Config{Username: "arg-user", Profile: "lab", CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-lab", Username: "arg-user", Password: "lab-pass"},
+		},
+		{
+			"profile completes partial helper output",
+			Config{Hostname: "ibox-arg", CredentialHelper: `echo '{"username": "helper-user"}'`, CredentialsFile: path},
+			SystemConfig{Hostname: "ibox-arg", Username: "helper-user", Password: "profile-pass"},
+		},
+		{
+			"helper gets the hostname",
+			Config{Hostname: "ibox-arg", Username: "arg-user", CredentialHelper: `echo "{\"password\": \"$IBOX_HOSTNAME-pass\"}"`},
+			SystemConfig{Hostname: "ibox-arg", Username: "arg-user", Password: "ibox-arg-pass"},
+		},
+		{
+			"missing default credentials file",
+			Config{Hostname: "ibox-arg", CredentialsFile: path + ".missing"},
+			SystemConfig{Hostname: "ibox-arg"},
+		},
+	}
+	for _, c := range cases {
+		config := c.config
+		if err := config.resolveCredentials(); err != nil {
+			t.Errorf("%v: unexpected error: %v", c.name, err)
+			continue
+		}
+		got := SystemConfig{Hostname: config.Hostname, Username: config.Username, Password: config.Password}
+		if got != c.expected {
+			t.Errorf("%v: resolved %+v, expected %+v", c.name, got, c.expected)
+		}
+	}
+}
+
+func TestResolveCredentialsSystems(t *testing.T) {
+	path := testCredentialsPath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	config := Config{
+		Username:         "arg-user",
+		Password:         "arg-pass",
+		CredentialsFile:  path,
+		CredentialHelper: `[ "$IBOX_SYSTEM" = "helper" ] && echo '{"password": "helper-pass"}' || echo '{}'`,
+		Systems: []SystemConfig{
+			{Name: "inherit", Hostname: "ibox-a"},
+			{Name: "explicit", Hostname: "ibox-b", Username: "b-user", Password: "b-pass"},
+			{Name: "profile", Profile: "lab"},
+			{Name: "helper", Hostname: "ibox-c", Username: "c-user"},
+		},
+	}
+	if err := config.resolveCredentials(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SystemConfig{
+		{Name: "inherit", Hostname: "ibox-a", Username: "arg-user", Password: "arg-pass"},
+		{Name: "explicit", Hostname: "ibox-b", Username: "b-user", Password: "b-pass"},
+		{Name: "profile", Profile: "lab", Hostname: "ibox-lab", Username: "lab-user", Password: "lab-pass"},
+		{Name: "helper", Hostname: "ibox-c", Username: "c-user", Password: "helper-pass"},
+	}
+	for i, system := range config.Systems {
+		if system != expected[i] {
+			t.Errorf("system %v resolved %+v, expected %+v", system.Name, system, expected[i])
+		}
+	}
+}
+
+func TestResolveCredentialsErrors(t *testing.T) {
+	path := testCredentialsPath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	cases := []struct {
+		name   string
+		config Config
+		output string
+	}{
+		{
+			"helper fails",
+			Config{Hostname: "ibox-arg", CredentialHelper: `echo "vault is sealed" >&2; exit 3`},
+			"vault is sealed",
+		},
+		{
+			"helper prints malformed output",
+			Config{Hostname: "ibox-arg", CredentialHelper: `echo 'username=admin'`},
+			"not valid JSON",
+		},
+		{
+			"helper prints a JSON list",
+			Config{Hostname: "ibox-arg", CredentialHelper: `echo '["admin"]'`},
+			"not valid JSON",
+		},
+		{
+			"unknown profile",
+			Config{Profile: "prod", CredentialsFile: path},
+			"profile prod not found",
+		},
+		{
+			"missing file of a requested profile",
+			Config{Profile: "lab", CredentialsFile: path + ".missing"},
+			"does not exist",
+		},
+		{
+			"unknown system profile",
+			Config{Hostname: "ibox-arg", Username: "u", Password: "p", CredentialsFile: path, Systems: []SystemConfig{{Name: "b", Profile: "prod"}}},
+			"profile prod not found",
+		},
+	}
+	for _, c := range cases {
+		config := c.config
+		err := config.resolveCredentials()
+		if err == nil {
+			t.Errorf("%v: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.output) {
+			t.Errorf("%v: expected the error to contain %q, got: %v", c.name, c.output, err)
+		}
+	}
+}
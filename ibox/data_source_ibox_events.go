@@ -0,0 +1,176 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func dataSourceIboxEvents() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIboxEventsRead,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"from": {
+				Description:  "Only return events at or after this RFC 3339 time e.g. 2018-01-02T15:04:05Z",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			"to": {
+				Description:  "Only return events before this RFC 3339 time",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			"level": {
+				Description: "Only return events of this level INFO, WARNING, ERROR or CRITICAL",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateFunc: validateStringInList([]string{
+					"INFO",
+					"WARNING",
+					"ERROR",
+					"CRITICAL",
+				}, false),
+			},
+			"code": {
+				Description: "Only return events with this code e.g. VOLUME_CREATED",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"reporter": {
+				Description: "Only return events of this reporter e.g. MGMT",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"username": {
+				Description: "Only return events caused by this user",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"events": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"level": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reporter": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"visibility": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"timestamp": {
+							Description: "Time of the event in RFC 3339 format",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// eventTimestamp converts an RFC 3339 time to the milliseconds used by the events api
+func eventTimestamp(value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR] %v is not an RFC 3339 time: %v", value, err)
+	}
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), nil
+}
+
+func dataSourceIboxEventsRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	filters := make([]string, 0)
+	if from := d.Get("from").(string); from != "" {
+		timestamp, err := eventTimestamp(from)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, eventFilter("timestamp", "ge", timestamp))
+	}
+	if to := d.Get("to").(string); to != "" {
+		timestamp, err := eventTimestamp(to)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, eventFilter("timestamp", "lt", timestamp))
+	}
+	for _, k := range []string{"level", "code", "reporter", "username"} {
+		if v := d.Get(k).(string); v != "" {
+			filters = append(filters, eventFilter(k, "eq", v))
+		}
+	}
+
+	events, err := client.ListEvents(filters)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(events))
+	found := make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		found = append(found, map[string]interface{}{
+			"id":          event.Id,
+			"code":        event.Code,
+			"level":       event.Level,
+			"description": event.Description,
+			"reporter":    event.Reporter,
+			"username":    event.Username,
+			"visibility":  event.Visibility,
+			"timestamp":   time.Unix(0, event.Timestamp*int64(time.Millisecond)).UTC().Format(time.RFC3339),
+		})
+		ids = append(ids, event.Id)
+	}
+
+	log.Printf("[INFO] Found %v events matching the filters", len(found))
+	if err := d.Set("events", found); err != nil {
+		return fmt.Errorf("[ERROR] Error setting events: %#v", err)
+	}
+	if err := d.Set("ids", ids); err != nil {
+		return fmt.Errorf("[ERROR] Error setting ids: %#v", err)
+	}
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(filters, "&"))))
+
+	return nil
+}
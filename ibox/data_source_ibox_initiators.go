@@ -0,0 +1,187 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func dataSourceIboxInitiators() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIboxInitiatorsRead,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"type": {
+				Description:  "Only return initiators of this type, FC, ISCSI or NVME",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringInList(portTypes, false),
+			},
+			"target_port": {
+				Description: "Only return initiators logged in to this target port address",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"wwn_prefix": {
+				Description: "Only return initiators whose address starts with this prefix, WWN prefixes may use colon or dash separators",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"include_assigned": {
+				Description: "Also return initiators which are already assigned to a host",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"addresses": {
+				Description: "Addresses of the matching initiators",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"initiators": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"targets": {
+							Description: "Target ports of the array the initiator is logged in to",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"address": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"node_id": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"port_id": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"fabric": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func initiatorLoggedInTo(initiator Initiator, target_port string) bool {
+	for _, target := range initiator.Targets {
+		if normalizeWWN(target.Address) == target_port {
+			return true
+		}
+	}
+	return false
+}
+
+// wwnPrefixRegex matches the leading bytes of a WWN, with or without separators
+var wwnPrefixRegex = regexp.MustCompile(`^([0-9a-fA-F]{2}[:-]?){1,8}$`)
+
+// normalizeAddressPrefix strips the separators of WWN prefixes the same way normalizeWWN does for
+// addresses, IQN and NQN prefixes keep their dashes and colons
+func normalizeAddressPrefix(prefix string) string {
+	if !wwnPrefixRegex.MatchString(prefix) {
+		return strings.ToLower(prefix)
+	}
+	prefix = strings.Replace(prefix, ":", "", -1)
+	prefix = strings.Replace(prefix, "-", "", -1)
+	return strings.ToLower(prefix)
+}
+
+// initiatorMatchesPrefix compares the normalized address with a prefix from normalizeAddressPrefix
+func initiatorMatchesPrefix(address string, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(address), prefix)
+}
+
+func dataSourceIboxInitiatorsRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	initiators, err := client.ListInitiators()
+	if err != nil {
+		return err
+	}
+
+	port_type := d.Get("type").(string)
+	target_port := normalizeWWN(d.Get("target_port").(string))
+	wwn_prefix := normalizeAddressPrefix(d.Get("wwn_prefix").(string))
+	include_assigned := d.Get("include_assigned").(bool)
+
+	addresses := make([]string, 0)
+	found := make([]map[string]interface{}, 0)
+	for _, initiator := range initiators {
+		address := normalizeWWN(initiator.Address)
+		if initiator.Host_id != 0 && !include_assigned {
+			continue
+		}
+		if port_type != "" && initiator.Type != port_type {
+			continue
+		}
+		if wwn_prefix != "" && !initiatorMatchesPrefix(address, wwn_prefix) {
+			continue
+		}
+		if target_port != "" && !initiatorLoggedInTo(initiator, target_port) {
+			continue
+		}
+
+		targets := make([]map[string]interface{}, 0, len(initiator.Targets))
+		for _, target := range initiator.Targets {
+			targets = append(targets, map[string]interface{}{
+				"address": normalizeWWN(target.Address),
+				"node_id": target.Node_id,
+				"port_id": target.Port_id,
+				"fabric":  target.Fabric,
+			})
+		}
+		found = append(found, map[string]interface{}{
+			"address": address,
+			"type":    initiator.Type,
+			"host_id": initiator.Host_id,
+			"targets": targets,
+		})
+		addresses = append(addresses, address)
+	}
+
+	log.Printf("[INFO] Found %v initiators matching the filters", len(found))
+	if err := d.Set("initiators", found); err != nil {
+		return fmt.Errorf("[ERROR] Error setting initiators: %#v", err)
+	}
+	if err := d.Set("addresses", addresses); err != nil {
+		return fmt.Errorf("[ERROR] Error setting addresses: %#v", err)
+	}
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(addresses, ","))))
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package ibox
+
+import (
+	"testing"
+)
+
+func TestInitiatorMatchesPrefix(t *testing.T) {
+	cases := []struct {
+		name    string
+		address string
+		prefix  string
+		matches bool
+	}{
+		{"wwn canonical prefix", "21000024ff913bff", "21000024", true},
+		{"wwn colon prefix", "21000024ff913bff", "21:00:00:24", true},
+		{"wwn colon prefix with trailing colon", "21000024ff913bff", "21:00:00:24:", true},
+		{"wwn dash upper case prefix", "21000024ff913bff", "21-00-00-24-FF", true},
+		{"wwn other prefix", "21000024ff913bff", "21:00:00:25", false},
+		{"wwn full address", "21000024ff913bff", "21:00:00:24:ff:91:3b:ff", true},
+		{"iqn prefix keeps dashes", "iqn.1998-01.com.vmware:esx01-4f2a", "iqn.1998-01.com.vmware", true},
+		{"iqn prefix keeps colons", "iqn.1998-01.com.vmware:esx01-4f2a", "iqn.1998-01.com.vmware:esx01", true},
+		{"iqn prefix is case insensitive", "iqn.1991-05.com.microsoft:Host01", "IQN.1991-05.com.microsoft:host", true},
+		{"iqn other prefix", "iqn.1998-01.com.vmware:esx01-4f2a", "iqn.1994-05.com.redhat", false},
+		{"nqn prefix", "nqn.2014-08.org.nvmexpress:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", "nqn.2014-08.org.nvmexpress:uuid:f81d", true},
+		{"nqn other prefix", "nqn.2014-08.org.nvmexpress:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", "nqn.2014-08.com.example", false},
+		{"eui prefix", "eui.02004567a425678d", "eui.0200", true},
+		{"wwn prefix does not match iqn", "iqn.1998-01.com.vmware:esx01", "21:00", false},
+	}
+	for _, c := range cases {
+		if got := initiatorMatchesPrefix(c.address, normalizeAddressPrefix(c.prefix)); got != c.matches {
+			t.Errorf("%v: prefix %q on %q = %v, expected %v", c.name, c.prefix, c.address, got, c.matches)
+		}
+	}
+}
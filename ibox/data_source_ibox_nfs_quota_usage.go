@@ -0,0 +1,110 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func dataSourceIboxNfsQuotaUsage() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIboxNfsQuotaUsageRead,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"filesystem_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"type": {
+				Description: "Report the usage of the user or of the group quotas",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "user",
+				ValidateFunc: validateStringInList([]string{
+					"user",
+					"group",
+				}, false),
+			},
+			"usage": {
+				Description: "Current usage per uid or gid",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uid": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"gid": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"used_capacity": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"used_inodes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"soft_capacity": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"hard_capacity": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"soft_inodes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"hard_inodes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIboxNfsQuotaUsageRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	kind := d.Get("type").(string)
+	filesystem_id := d.Get("filesystem_id").(int)
+
+	quotas, err := client.ListNfsQuotas(kind, filesystem_id)
+	if err != nil {
+		return err
+	}
+
+	usage := make([]map[string]interface{}, 0, len(quotas))
+	for i := range quotas {
+		entry := map[string]interface{}{
+			"used_capacity": quotas[i].Used_capacity,
+			"used_inodes":   quotas[i].Used_inodes,
+			"soft_capacity": quotas[i].Soft_capacity,
+			"hard_capacity": quotas[i].Hard_capacity,
+			"soft_inodes":   quotas[i].Soft_inodes,
+			"hard_inodes":   quotas[i].Hard_inodes,
+		}
+		entry[quotaOwnerKey(kind)] = quotaOwner(kind, &quotas[i])
+		usage = append(usage, entry)
+	}
+
+	log.Printf("[INFO] Found %v nfs %v quotas on filesystem: %v", len(usage), kind, filesystem_id)
+	if err := d.Set("usage", usage); err != nil {
+		return fmt.Errorf("[ERROR] Error setting usage: %#v", err)
+	}
+	d.SetId(strconv.Itoa(filesystem_id) + "/" + kind)
+
+	return nil
+}
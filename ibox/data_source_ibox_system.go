@@ -0,0 +1,91 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func dataSourceIboxSystem() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIboxSystemRead,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"serial_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"model": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Description: "Software version of the array e.g. 5.0.10",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"physical_capacity": {
+				Description: "Total physical capacity in bytes",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"virtual_capacity": {
+				Description: "Total virtual capacity in bytes",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"free_physical_space": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"free_virtual_space": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"health_state": {
+				Description: "Operational state of the array e.g. ACTIVE",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceIboxSystemRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	system, err := client.ReadSystem()
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] System: %v serial: %v runs version: %v", system.Name, system.Serial_number, system.Version)
+
+	d.Set("name", system.Name)
+	d.Set("serial_number", system.Serial_number)
+	d.Set("model", system.Model_name)
+	d.Set("version", system.Version)
+	if system.Capacity != nil {
+		d.Set("physical_capacity", system.Capacity.Total_physical_capacity)
+		d.Set("virtual_capacity", system.Capacity.Total_virtual_capacity)
+		d.Set("free_physical_space", system.Capacity.Free_physical_space)
+		d.Set("free_virtual_space", system.Capacity.Free_virtual_space)
+	}
+	if system.Operational_state != nil {
+		d.Set("health_state", system.Operational_state.State)
+	}
+	if system.Serial_number == 0 {
+		return fmt.Errorf("[ERROR] The system did not report its serial number")
+	}
+	d.SetId(strconv.Itoa(system.Serial_number))
+
+	return nil
+}
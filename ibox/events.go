@@ -0,0 +1,104 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"strconv"
+)
+
+// eventFilter returns an events query parameter, the value is escaped
+func eventFilter(field string, operator string, value string) string {
+	return field + "=" + operator + ":" + url.QueryEscape(value)
+}
+
+// withEventIds adds the event_ids attribute to every resource and records the events
+// caused by create and update when record_event_ids is enabled
+func withEventIds(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range resources {
+		r.Schema["event_ids"] = &schema.Schema{
+			Description: "Ids of the array events caused by the last create or update, only with record_event_ids",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		}
+		r.Create = recordEventIds(r.Create)
+		if r.Update != nil {
+			r.Update = recordEventIds(r.Update)
+		}
+		r.Delete = serializeEvents(r.Delete)
+	}
+	return resources
+}
+
+// recordEventIds wraps a create or update function and stores the events of the provider user
+// between the newest event before and the newest event after the operation in event_ids.
+// Changes on the array are serialized while recording, so parallel operations of the
+// provider cannot show up in each other's events.
+func recordEventIds(op func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client, err := getClient(d, meta)
+		if err != nil {
+			return err
+		}
+		if !client.RecordEventIds {
+			return op(d, meta)
+		}
+
+		client.eventsLock.Lock()
+		defer client.eventsLock.Unlock()
+
+		first_id, err := client.LastEventId()
+		if err != nil {
+			return err
+		}
+		if err := op(d, meta); err != nil {
+			return err
+		}
+		if d.Id() == "" {
+			return nil
+		}
+		last_id, err := client.LastEventId()
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int, 0)
+		if last_id > first_id {
+			events, err := client.ListEvents([]string{
+				eventFilter("id", "gt", strconv.Itoa(first_id)),
+				eventFilter("id", "le", strconv.Itoa(last_id)),
+				eventFilter("username", "eq", client.Username),
+			})
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				ids = append(ids, event.Id)
+			}
+		}
+		log.Printf("[INFO] Operation on %v caused the events: %v", d.Id(), ids)
+		if err := d.Set("event_ids", ids); err != nil {
+			return fmt.Errorf("[ERROR] Error setting event_ids: %#v", err)
+		}
+		return nil
+	}
+}
+
+// serializeEvents wraps a delete function, so its events are not recorded by a parallel create or update
+func serializeEvents(op func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client, err := getClient(d, meta)
+		if err != nil {
+			return err
+		}
+		if client.RecordEventIds {
+			client.eventsLock.Lock()
+			defer client.eventsLock.Unlock()
+		}
+		return op(d, meta)
+	}
+}
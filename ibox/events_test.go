@@ -0,0 +1,127 @@
+package ibox
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// fakeEvents is the event log of the fake array
+type fakeEvents struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (e *fakeEvents) add(username string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := len(e.events) + 1
+	e.events = append(e.events, Event{Id: id, Code: "VOLUME_CREATED", Username: username})
+	return id
+}
+
+// result answers GET /events, it supports sort=-id&page_size=1 and the id and username filters
+func (e *fakeEvents) result(r fakeRequest) (string, bool) {
+	if r.Method != "GET" || r.Path != "/events" {
+		return "", false
+	}
+	query, _ := url.ParseQuery(r.Query)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	found := make([]Event, 0)
+	if query.Get("sort") == "-id" {
+		if len(e.events) > 0 {
+			found = append(found, e.events[len(e.events)-1])
+		}
+	} else {
+		for _, event := range e.events {
+			if eventMatches(event, query) {
+				found = append(found, event)
+			}
+		}
+	}
+	out, _ := json.Marshal(found)
+	return string(out), true
+}
+
+func eventMatches(event Event, query url.Values) bool {
+	for _, filter := range query["id"] {
+		parts := strings.SplitN(filter, ":", 2)
+		id, _ := strconv.Atoi(parts[1])
+		if parts[0] == "gt" && event.Id <= id || parts[0] == "le" && event.Id > id {
+			return false
+		}
+	}
+	for _, filter := range query["username"] {
+		if filter != "eq:"+event.Username {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecordEventIdsParallel(t *testing.T) {
+	events := &fakeEvents{}
+	api, client := newFakeApi(t, map[string]string{})
+	defer api.Close()
+	api.resultFunc = events.result
+	client.RecordEventIds = true
+	meta := &ClientPool{Default: client}
+
+	// Every operation causes one event, and another user changes the array at the same time
+	create := recordEventIds(func(d *schema.ResourceData, meta interface{}) error {
+		id := events.add("admin")
+		events.add("someone-else")
+		time.Sleep(20 * time.Millisecond)
+		d.SetId(strconv.Itoa(id))
+		return nil
+	})
+
+	resources := make([]*schema.ResourceData, 4)
+	var wg sync.WaitGroup
+	for i := range resources {
+		resources[i] = schema.TestResourceDataRaw(t, withEventIds(map[string]*schema.Resource{
+			"ibox_volume": resourceIboxVolume(),
+		})["ibox_volume"].Schema, map[string]interface{}{})
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			if err := create(d, meta); err != nil {
+				t.Error(err)
+			}
+		}(resources[i])
+	}
+	wg.Wait()
+
+	for _, d := range resources {
+		ids := d.Get("event_ids").([]interface{})
+		if len(ids) != 1 || strconv.Itoa(ids[0].(int)) != d.Id() {
+			t.Errorf("resource %v recorded the events: %v, expected only its own event", d.Id(), ids)
+		}
+	}
+}
+
+func TestRecordEventIdsDisabled(t *testing.T) {
+	api, client := newFakeApi(t, map[string]string{})
+	defer api.Close()
+	meta := &ClientPool{Default: client}
+
+	create := recordEventIds(func(d *schema.ResourceData, meta interface{}) error {
+		d.SetId("1")
+		return nil
+	})
+	d := schema.TestResourceDataRaw(t, resourceIboxVolume().Schema, map[string]interface{}{})
+	if err := create(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.requestsTo("GET", "/events")) != 0 {
+		t.Errorf("events were read although record_event_ids is disabled")
+	}
+}
@@ -0,0 +1,140 @@
+package ibox
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+type fakeRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]interface{}
+}
+
+// fakeApi answers API calls with canned results and records every request
+type fakeApi struct {
+	mu        sync.Mutex
+	server    *httptest.Server
+	results   map[string]string
+	requests  []fakeRequest
+	onRequest func(r fakeRequest)
+	// resultFunc answers the calls which need a computed result, results are used when it returns false
+	resultFunc func(r fakeRequest) (string, bool)
+}
+
+// newFakeApi starts a fake array, results maps "METHOD /path" to the JSON result of the call
+func newFakeApi(t *testing.T, results map[string]string) (*fakeApi, *Client) {
+	api := &fakeApi{results: results}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serve))
+	client, err := NewClient("admin", "123456", strings.TrimPrefix(api.server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api, client
+}
+
+func (api *fakeApi) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/rest")
+	request := fakeRequest{Method: r.Method, Path: path, Query: r.URL.RawQuery}
+	if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+		json.Unmarshal(body, &request.Body)
+	}
+
+	api.mu.Lock()
+	api.requests = append(api.requests, request)
+	result, ok := api.results[r.Method+" "+path]
+	onRequest := api.onRequest
+	resultFunc := api.resultFunc
+	api.mu.Unlock()
+
+	if resultFunc != nil {
+		if computed, found := resultFunc(request); found {
+			result, ok = computed, true
+		}
+	}
+
+	if onRequest != nil {
+		onRequest(request)
+	}
+	if !ok {
+		w.WriteHeader(404)
+		w.Write([]byte(`{"error": {"code": "NOT_FOUND"}}`))
+		return
+	}
+	if r.Method == "POST" {
+		w.WriteHeader(201)
+	}
+	var list []json.RawMessage
+	count := 1
+	if json.Unmarshal([]byte(result), &list) == nil {
+		count = len(list)
+	}
+	w.Write([]byte(`{"result": ` + result + `, "metadata": {"pages_total": 1, "number_of_objects": ` + strconv.Itoa(count) + `}}`))
+}
+
+// requestsTo returns the recorded requests with the method and path
+func (api *fakeApi) requestsTo(method string, path string) []fakeRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	found := make([]fakeRequest, 0)
+	for _, request := range api.requests {
+		if request.Method == method && request.Path == path {
+			found = append(found, request)
+		}
+	}
+	return found
+}
+
+func (api *fakeApi) Close() {
+	api.server.Close()
+}
+
+// changeRequests returns the POST and DELETE calls under the path prefix in the order they were sent
+func changeRequests(api *fakeApi, prefix string) []fakeRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	found := make([]fakeRequest, 0)
+	for _, request := range api.requests {
+		if (request.Method == "POST" || request.Method == "DELETE") && strings.HasPrefix(request.Path, prefix) {
+			found = append(found, request)
+		}
+	}
+	return found
+}
+
+// testResourceState builds the state of an existing object from its configuration
+func testResourceState(t *testing.T, r *schema.Resource, id int, raw map[string]interface{}) *terraform.InstanceState {
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.Set("id", id)
+	d.SetId(strconv.Itoa(id))
+	return d.State()
+}
+
+func testApplyResource(t *testing.T, r *schema.Resource, client *Client, state *terraform.InstanceState, raw map[string]interface{}) {
+	meta := &ClientPool{Default: client}
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.RequiresNew() {
+		t.Fatalf("expected an in place update, got: %v", diff)
+	}
+	if _, err := r.Apply(state, diff, meta); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+// Minimal software version of the array for the optional features
+var featureVersions = map[string]string{
+	"compression": "3.0",
+}
+
+// detectVersion fetches the software version of the array once, when the provider is configured.
+// Feature gates are skipped when the version cannot be read.
+func (client *Client) detectVersion() {
+	system, err := client.ReadSystem()
+	if err != nil {
+		log.Printf("[WARN] Reading the software version of %v failed, feature checks are disabled: %v", client.Hostname, err)
+		return
+	}
+	v, err := version.NewVersion(system.Version)
+	if err != nil {
+		log.Printf("[WARN] Software version: %q of %v cannot be parsed, feature checks are disabled: %v", system.Version, client.Hostname, err)
+		return
+	}
+	log.Printf("[INFO] %v runs software version: %v", client.Hostname, v)
+	client.version = v
+}
+
+// RequireFeature fails when the array is known to run a software version without the feature
+func (client *Client) RequireFeature(feature string) error {
+	minimal, ok := featureVersions[feature]
+	if !ok {
+		return fmt.Errorf("[ERROR] unknown feature: %v", feature)
+	}
+	if client.version == nil {
+		return nil
+	}
+	if client.version.LessThan(version.Must(version.NewVersion(minimal))) {
+		return fmt.Errorf("[ERROR] %v requires %v+, %v runs %v", feature, minimal, client.Hostname, client.version)
+	}
+	return nil
+}
+
+// requireFeatureIf fails the plan when the attribute is set and the array does not support the feature
+func requireFeatureIf(d *schema.ResourceDiff, meta interface{}, key string, feature string) error {
+	if _, ok := d.GetOk(key); !ok {
+		return nil
+	}
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	return client.RequireFeature(feature)
+}
@@ -0,0 +1,91 @@
+package ibox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestRequireFeature(t *testing.T) {
+	cases := []struct {
+		name    string
+		system  string
+		feature string
+		message string
+	}{
+		{"below the gate", `{"version": "2.2.10"}`, "compression", "compression requires 3.0+"},
+		{"below the gate with build", `{"version": "2.2.10.4"}`, "compression", "compression requires 3.0+"},
+		{"at the gate", `{"version": "3.0"}`, "compression", ""},
+		{"at the gate with patch", `{"version": "3.0.0"}`, "compression", ""},
+		{"above the gate", `{"version": "5.0.3.12"}`, "compression", ""},
+		{"version cannot be parsed", `{"version": "unknown"}`, "compression", ""},
+		{"version cannot be read", "", "compression", ""},
+		{"unknown feature", `{"version": "5.0"}`, "teleport", "unknown feature: teleport"},
+		{"unknown feature without version", "", "teleport", "unknown feature: teleport"},
+	}
+	for _, c := range cases {
+		results := map[string]string{}
+		if c.system != "" {
+			results["GET /system"] = c.system
+		}
+		api, client := newFakeApi(t, results)
+		client.detectVersion()
+		err := client.RequireFeature(c.feature)
+		api.Close()
+
+		if c.message == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%v: expected an error containing %q, got: %v", c.name, c.message, err)
+		}
+	}
+}
+
+func TestFeatureVersionsParse(t *testing.T) {
+	for feature, minimal := range featureVersions {
+		if _, err := version.NewVersion(minimal); err != nil {
+			t.Errorf("minimal version: %q of feature: %v cannot be parsed: %v", minimal, feature, err)
+		}
+	}
+}
+
+func TestVolumeCompressionGatedAtPlan(t *testing.T) {
+	cases := map[string]bool{
+		"2.2.10": true,
+		"3.0":    false,
+		"":       false,
+	}
+	for system_version, fails := range cases {
+		results := map[string]string{}
+		if system_version != "" {
+			results["GET /system"] = `{"version": "` + system_version + `"}`
+		}
+		api, client := newFakeApi(t, results)
+		client.detectVersion()
+		api.Close()
+
+		c, err := config.NewRawConfig(map[string]interface{}{
+			"name":                "v1",
+			"pool_id":             1,
+			"size":                1000000000,
+			"compression_enabled": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = resourceIboxVolume().Diff(nil, terraform.NewResourceConfig(c), &ClientPool{Default: client})
+		if fails && err == nil {
+			t.Errorf("version %q: expected compression_enabled to fail the plan", system_version)
+		}
+		if !fails && err != nil {
+			t.Errorf("version %q: unexpected plan error: %v", system_version, err)
+		}
+	}
+}
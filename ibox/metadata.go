@@ -0,0 +1,160 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+const (
+	managedByKey   = "managed-by"
+	managedByValue = "terraform"
+)
+
+func metadataSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Metadata key/value pairs of the object",
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func metadataAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "All metadata key/value pairs of the object, including the provider default metadata",
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func expandMetadata(raw interface{}) map[string]string {
+	kv := make(map[string]string)
+	for k, v := range raw.(map[string]interface{}) {
+		kv[k] = v.(string)
+	}
+	return kv
+}
+
+// expandDefaultMetadata returns the metadata of the provider default_metadata block, with the
+// managed-by stamp unless the block sets that key itself
+func expandDefaultMetadata(defaults map[string]interface{}) map[string]string {
+	kv := expandMetadata(defaults["metadata"])
+	if defaults["managed_by_terraform"].(bool) {
+		if _, ok := kv[managedByKey]; !ok {
+			kv[managedByKey] = managedByValue
+		}
+	}
+	return kv
+}
+
+// diffMetadata returns the keys to set and the keys to delete to get from old to new
+func diffMetadata(old map[string]string, new map[string]string) (map[string]string, []string) {
+	toSet := make(map[string]string)
+	for k, v := range new {
+		if oldv, ok := old[k]; !ok || oldv != v {
+			toSet[k] = v
+		}
+	}
+	toDelete := make([]string, 0)
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			toDelete = append(toDelete, k)
+		}
+	}
+	return toSet, toDelete
+}
+
+// syncMetadata applies the changed metadata keys one by one to the object
+func syncMetadata(client *Client, object_id int, old map[string]string, new map[string]string) error {
+	toSet, toDelete := diffMetadata(old, new)
+	if len(toSet) > 0 {
+		log.Printf("[DEBUG] Setting metadata keys: %v of object id: %v", toSet, object_id)
+		if err := client.SetMetadata(object_id, toSet); err != nil {
+			return err
+		}
+	}
+	for _, k := range toDelete {
+		log.Printf("[DEBUG] Deleting metadata key: %v of object id: %v", k, object_id)
+		if err := client.DeleteMetadata(object_id, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// effectiveMetadata merges the provider default metadata with the resource metadata,
+// keys of the resource take precedence
+func effectiveMetadata(client *Client, resourceMetadata map[string]string) map[string]string {
+	kv := make(map[string]string)
+	for k, v := range client.DefaultMetadata {
+		kv[k] = v
+	}
+	for k, v := range resourceMetadata {
+		kv[k] = v
+	}
+	return kv
+}
+
+// customizeMetadataDiff plans metadata_all, so changes of the provider default metadata
+// are applied to objects whose own metadata did not change
+func customizeMetadataDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || d.HasChange("metadata") {
+		return d.SetNewComputed("metadata_all")
+	}
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	newAll := effectiveMetadata(client, expandMetadata(d.Get("metadata")))
+	oldAll := expandMetadata(d.Get("metadata_all"))
+	if toSet, toDelete := diffMetadata(oldAll, newAll); len(toSet) > 0 || len(toDelete) > 0 {
+		return d.SetNew("metadata_all", newAll)
+	}
+	return nil
+}
+
+// createMetadata sets the configured and the default metadata of a newly created object
+func createMetadata(d *schema.ResourceData, client *Client, object_id int) error {
+	return syncMetadata(client, object_id, map[string]string{}, effectiveMetadata(client, expandMetadata(d.Get("metadata"))))
+}
+
+// updateMetadata syncs the metadata keys changed in the configuration or in the provider defaults
+func updateMetadata(d *schema.ResourceData, client *Client, object_id int) error {
+	if !d.HasChange("metadata") && !d.HasChange("metadata_all") {
+		return nil
+	}
+	oldAll, _ := d.GetChange("metadata_all")
+	return syncMetadata(client, object_id, expandMetadata(oldAll), effectiveMetadata(client, expandMetadata(d.Get("metadata"))))
+}
+
+// readMetadata stores the metadata of the object for drift detection. Keys which come
+// from the provider default metadata are only kept in metadata_all, unless the resource sets them.
+func readMetadata(d *schema.ResourceData, client *Client, object_id int) error {
+	kv, err := client.ReadMetadata(object_id)
+	if err != nil {
+		return err
+	}
+	configured := expandMetadata(d.Get("metadata"))
+	own := make(map[string]string)
+	for k, v := range kv {
+		if defv, ok := client.DefaultMetadata[k]; ok && defv == v {
+			if _, ok := configured[k]; !ok {
+				continue
+			}
+		}
+		own[k] = v
+	}
+	if err := d.Set("metadata", own); err != nil {
+		return fmt.Errorf("[ERROR] Error setting metadata: %#v", err)
+	}
+	if err := d.Set("metadata_all", kv); err != nil {
+		return fmt.Errorf("[ERROR] Error setting metadata_all: %#v", err)
+	}
+	return nil
+}
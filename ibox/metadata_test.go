@@ -0,0 +1,301 @@
+package ibox
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestDiffMetadata(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      map[string]string
+		new      map[string]string
+		toSet    map[string]string
+		toDelete []string
+	}{
+		{"unchanged", map[string]string{"a": "1"}, map[string]string{"a": "1"}, map[string]string{}, []string{}},
+		{"add", map[string]string{"a": "1"}, map[string]string{"a": "1", "b": "2"}, map[string]string{"b": "2"}, []string{}},
+		{"change", map[string]string{"a": "1"}, map[string]string{"a": "2"}, map[string]string{"a": "2"}, []string{}},
+		{"remove", map[string]string{"a": "1", "b": "2"}, map[string]string{"a": "1"}, map[string]string{}, []string{"b"}},
+		{"remove all", map[string]string{"a": "1", "b": "2"}, map[string]string{}, map[string]string{}, []string{"a", "b"}},
+		{"add change and remove", map[string]string{"a": "1", "b": "2", "c": "3"}, map[string]string{"a": "1", "b": "20", "d": "4"}, map[string]string{"b": "20", "d": "4"}, []string{"c"}},
+		{"empty value is a change", map[string]string{"a": "1"}, map[string]string{"a": ""}, map[string]string{"a": ""}, []string{}},
+	}
+	for _, c := range cases {
+		toSet, toDelete := diffMetadata(c.old, c.new)
+		sort.Strings(toDelete)
+		if !reflect.DeepEqual(toSet, c.toSet) {
+			t.Errorf("%v: keys to set = %v, expected %v", c.name, toSet, c.toSet)
+		}
+		if !reflect.DeepEqual(toDelete, c.toDelete) {
+			t.Errorf("%v: keys to delete = %v, expected %v", c.name, toDelete, c.toDelete)
+		}
+	}
+}
+
+// newFakeMetadataApi answers every metadata call of object id 1 and the host calls of testApplyHost
+func newFakeMetadataApi(t *testing.T, current string) (*fakeApi, *Client) {
+	api, client := newFakeApi(t, map[string]string{
+		"GET /hosts/1":    testHostResult,
+		"PUT /hosts/1":    testHostResult,
+		"GET /metadata/1": current,
+		"PUT /metadata/1": `[]`,
+	})
+	api.resultFunc = func(r fakeRequest) (string, bool) {
+		if r.Method == "DELETE" && strings.HasPrefix(r.Path, "/metadata/1/") {
+			return `{}`, true
+		}
+		return "", false
+	}
+	return api, client
+}
+
+// metadataRequests returns the metadata changes of the apply in the order they were sent
+func metadataRequests(api *fakeApi) []fakeRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	found := make([]fakeRequest, 0)
+	for _, request := range api.requests {
+		if request.Method != "GET" && strings.HasPrefix(request.Path, "/metadata/") {
+			found = append(found, request)
+		}
+	}
+	return found
+}
+
+func testHostMetadataState(metadata map[string]string, metadataAll map[string]string) *terraform.InstanceState {
+	state := testHostState()
+	state.Attributes["metadata.%"] = strconv.Itoa(len(metadata))
+	for k, v := range metadata {
+		state.Attributes["metadata."+k] = v
+	}
+	state.Attributes["metadata_all.%"] = strconv.Itoa(len(metadataAll))
+	for k, v := range metadataAll {
+		state.Attributes["metadata_all."+k] = v
+	}
+	return state
+}
+
+// testApplyHostMetadata plans and applies the host with the configured metadata, it returns nil when nothing changes
+func testApplyHostMetadata(t *testing.T, client *Client, state *terraform.InstanceState, metadata map[string]interface{}) *terraform.InstanceDiff {
+	meta := &ClientPool{Default: client}
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":                            "h1",
+		"security_method":                 "CHAP",
+		"security_chap_inbound_username":  "old-user",
+		"security_chap_inbound_secret":    "inbound-secret-1",
+		"security_chap_outbound_username": "out-user",
+		"security_chap_outbound_secret":   "outbound-secret1",
+		"metadata":                        metadata,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := resourceIboxHost()
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Empty() {
+		return nil
+	}
+	if _, err := r.Apply(state, diff, meta); err != nil {
+		t.Fatal(err)
+	}
+	return diff
+}
+
+func TestResourceMetadataChanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		state    map[string]string
+		config   map[string]interface{}
+		toSet    map[string]interface{}
+		toDelete []string
+	}{
+		{"add", map[string]string{"a": "1"}, map[string]interface{}{"a": "1", "b": "2"}, map[string]interface{}{"b": "2"}, nil},
+		{"change", map[string]string{"a": "1"}, map[string]interface{}{"a": "2"}, map[string]interface{}{"a": "2"}, nil},
+		{"remove", map[string]string{"a": "1", "b": "2"}, map[string]interface{}{"a": "1"}, nil, []string{"b"}},
+		{"add and remove", map[string]string{"a": "1", "b": "2"}, map[string]interface{}{"a": "1", "c": "3"}, map[string]interface{}{"c": "3"}, []string{"b"}},
+	}
+	for _, c := range cases {
+		api, client := newFakeMetadataApi(t, `[]`)
+		diff := testApplyHostMetadata(t, client, testHostMetadataState(c.state, c.state), c.config)
+		requests := metadataRequests(api)
+		api.Close()
+		if diff == nil {
+			t.Errorf("%v: expected a metadata diff", c.name)
+			continue
+		}
+
+		expected := 0
+		if c.toSet != nil {
+			expected++
+			if requests[0].Method != "PUT" || !reflect.DeepEqual(requests[0].Body, c.toSet) {
+				t.Errorf("%v: expected PUT of %v first, got: %v", c.name, c.toSet, requests)
+			}
+		}
+		for _, k := range c.toDelete {
+			expected++
+			found := false
+			for _, request := range requests {
+				found = found || (request.Method == "DELETE" && request.Path == "/metadata/1/"+k)
+			}
+			if !found {
+				t.Errorf("%v: key %v was not deleted: %v", c.name, k, requests)
+			}
+		}
+		if len(requests) != expected {
+			t.Errorf("%v: expected %v metadata changes, got: %v", c.name, expected, requests)
+		}
+	}
+}
+
+func TestResourceMetadataUnchanged(t *testing.T) {
+	api, client := newFakeMetadataApi(t, `[]`)
+	defer api.Close()
+
+	metadata := map[string]string{"a": "1"}
+	if diff := testApplyHostMetadata(t, client, testHostMetadataState(metadata, metadata), map[string]interface{}{"a": "1"}); diff != nil {
+		t.Errorf("expected no diff for unchanged metadata, got: %v", diff)
+	}
+	if requests := metadataRequests(api); len(requests) != 0 {
+		t.Errorf("expected no metadata changes, got: %v", requests)
+	}
+}
+
+func TestExpandDefaultMetadata(t *testing.T) {
+	cases := []struct {
+		name     string
+		defaults map[string]interface{}
+		expected map[string]string
+	}{
+		{
+			"without stamp",
+			map[string]interface{}{"metadata": map[string]interface{}{"team": "storage"}, "managed_by_terraform": false},
+			map[string]string{"team": "storage"},
+		},
+		{
+			"stamp",
+			map[string]interface{}{"metadata": map[string]interface{}{"team": "storage"}, "managed_by_terraform": true},
+			map[string]string{"team": "storage", "managed-by": "terraform"},
+		},
+		{
+			"stamp without metadata",
+			map[string]interface{}{"metadata": map[string]interface{}{}, "managed_by_terraform": true},
+			map[string]string{"managed-by": "terraform"},
+		},
+		{
+			"explicit managed-by wins over the stamp",
+			map[string]interface{}{"metadata": map[string]interface{}{"managed-by": "terraform-prod"}, "managed_by_terraform": true},
+			map[string]string{"managed-by": "terraform-prod"},
+		},
+	}
+	for _, c := range cases {
+		if got := expandDefaultMetadata(c.defaults); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%v: default metadata = %v, expected %v", c.name, got, c.expected)
+		}
+	}
+}
+
+func TestEffectiveMetadata(t *testing.T) {
+	client := &Client{DefaultMetadata: map[string]string{"team": "storage", "managed-by": "terraform"}}
+	cases := []struct {
+		name     string
+		resource map[string]string
+		expected map[string]string
+	}{
+		{"defaults only", map[string]string{}, map[string]string{"team": "storage", "managed-by": "terraform"}},
+		{"merged", map[string]string{"app": "db"}, map[string]string{"team": "storage", "managed-by": "terraform", "app": "db"}},
+		{"resource overrides default", map[string]string{"team": "dba"}, map[string]string{"team": "dba", "managed-by": "terraform"}},
+	}
+	for _, c := range cases {
+		if got := effectiveMetadata(client, c.resource); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%v: effective metadata = %v, expected %v", c.name, got, c.expected)
+		}
+	}
+}
+
+func TestResourceDefaultMetadataChanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		defaults map[string]string
+		metadata map[string]string
+		all      map[string]string
+		config   map[string]interface{}
+		toSet    map[string]interface{}
+	}{
+		{
+			"new default is applied without resource changes",
+			map[string]string{"managed-by": "terraform"},
+			map[string]string{"app": "db"},
+			map[string]string{"app": "db"},
+			map[string]interface{}{"app": "db"},
+			map[string]interface{}{"managed-by": "terraform"},
+		},
+		{
+			"changed default is applied",
+			map[string]string{"team": "storage"},
+			map[string]string{},
+			map[string]string{"team": "backup"},
+			map[string]interface{}{},
+			map[string]interface{}{"team": "storage"},
+		},
+		{
+			"resource overrides default",
+			map[string]string{"team": "storage"},
+			map[string]string{},
+			map[string]string{"team": "storage"},
+			map[string]interface{}{"team": "dba"},
+			map[string]interface{}{"team": "dba"},
+		},
+	}
+	for _, c := range cases {
+		api, client := newFakeMetadataApi(t, `[]`)
+		client.DefaultMetadata = c.defaults
+		diff := testApplyHostMetadata(t, client, testHostMetadataState(c.metadata, c.all), c.config)
+		requests := metadataRequests(api)
+		api.Close()
+		if diff == nil {
+			t.Errorf("%v: expected a metadata diff", c.name)
+			continue
+		}
+		if len(requests) != 1 || requests[0].Method != "PUT" || !reflect.DeepEqual(requests[0].Body, c.toSet) {
+			t.Errorf("%v: expected only a PUT of %v, got: %v", c.name, c.toSet, requests)
+		}
+	}
+}
+
+func TestReadMetadataKeepsDefaultsInMetadataAll(t *testing.T) {
+	api, client := newFakeApi(t, map[string]string{
+		"GET /metadata/1": `[
+			{"key": "managed-by", "value": "terraform"},
+			{"key": "team", "value": "dba"},
+			{"key": "app", "value": "db"}
+		]`,
+	})
+	defer api.Close()
+	client.DefaultMetadata = map[string]string{"managed-by": "terraform", "team": "storage"}
+
+	d := resourceIboxHost().TestResourceData()
+	d.Set("metadata", map[string]interface{}{"app": "db"})
+	if err := readMetadata(d, client, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// team differs from the default, so it was set on the object and is drift of the resource
+	expected := map[string]string{"team": "dba", "app": "db"}
+	if got := expandMetadata(d.Get("metadata")); !reflect.DeepEqual(got, expected) {
+		t.Errorf("metadata = %v, expected %v", got, expected)
+	}
+	expectedAll := map[string]string{"managed-by": "terraform", "team": "dba", "app": "db"}
+	if got := expandMetadata(d.Get("metadata_all")); !reflect.DeepEqual(got, expectedAll) {
+		t.Errorf("metadata_all = %v, expected %v", got, expectedAll)
+	}
+}
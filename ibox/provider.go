@@ -10,32 +10,163 @@ func Provider() terraform.ResourceProvider {
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("IBOX_USERNAME", nil),
 				Description: "iBox username",
 			},
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("IBOX_PASSWORD", nil),
 				Description: "iBox password",
 			},
 			"hostname": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("IBOX_HOSTNAME", nil),
 				Description: "iBox hostname",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("IBOX_PROFILE", ""),
+				Description: "Profile in the credentials file, used for settings missing from the configuration",
+			},
+			"credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("IBOX_CREDENTIALS_FILE", defaultCredentialsFile),
+				Description: "Path of the INI credentials file",
+			},
+			"credential_helper": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("IBOX_CREDENTIAL_HELPER", ""),
+				Description: "Command printing JSON credentials (hostname, username, password) on its standard output",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("IBOX_REQUESTS_PER_SECOND", 0),
+				Description:  "Maximum rate of API requests per second, 0 disables rate limiting",
+				ValidateFunc: validateFloatGeqThan(0),
+			},
+			"burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("IBOX_BURST", 1),
+				Description:  "Number of API requests allowed to exceed requests_per_second in a burst",
+				ValidateFunc: validateIntegerGeqThan(1),
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("IBOX_MAX_CONCURRENT_REQUESTS", 0),
+				Description:  "Maximum number of API requests in flight, 0 means unlimited",
+				ValidateFunc: validateIntegerGeqThan(0),
+			},
+			"record_event_ids": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("IBOX_RECORD_EVENT_IDS", false),
+				Description: "Store the ids of the array events caused by create and update in the event_ids of the resources",
+			},
+			"log_http_bodies": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("IBOX_LOG_HTTP_BODIES", false),
+				Description: "Log HTTP request and response bodies at DEBUG level, sensitive fields are masked",
+			},
+			"default_metadata": {
+				Description: "Metadata applied to every object managed by the provider",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"metadata": {
+							Description: "Metadata key/value pairs, keys set on a resource take precedence",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"managed_by_terraform": {
+							Description: "Stamp the managed-by=terraform key on every object",
+							Type:        schema.TypeBool,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"system": {
+				Description: "Additional InfiniBox systems, resources select one with their system argument",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "Name used by the system argument of resources",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"hostname": {
+							Description: "iBox hostname, defaults to the hostname of the profile",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"username": {
+							Description: "iBox username, defaults to the provider username",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"password": {
+							Description: "iBox password, defaults to the provider password",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"profile": {
+							Description: "Profile in the credentials file with the hostname and credentials of the system",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
 		},
 
-		ResourcesMap: map[string]*schema.Resource{
-			"ibox_host_cluster": resourceIboxHostCluster(),
-			"ibox_host":         resourceIboxHost(),
-			"ibox_pool":         resourceIboxPool(),
-			"ibox_volume":       resourceIboxVolume(),
-			"ibox_lun":          resourceIboxLun(),
+		DataSourcesMap: map[string]*schema.Resource{
+			"ibox_initiators":      dataSourceIboxInitiators(),
+			"ibox_nfs_quota_usage": dataSourceIboxNfsQuotaUsage(),
+			"ibox_system":          dataSourceIboxSystem(),
+			"ibox_events":          dataSourceIboxEvents(),
 		},
 
+		ResourcesMap: withEventIds(map[string]*schema.Resource{
+			"ibox_host_cluster":        resourceIboxHostCluster(),
+			"ibox_host":                resourceIboxHost(),
+			"ibox_host_port":           resourceIboxHostPort(),
+			"ibox_pool":                resourceIboxPool(),
+			"ibox_volume":              resourceIboxVolume(),
+			"ibox_lun":                 resourceIboxLun(),
+			"ibox_network_space":       resourceIboxNetworkSpace(),
+			"ibox_network_interface":   resourceIboxNetworkInterface(),
+			"ibox_smb_share":           resourceIboxSmbShare(),
+			"ibox_smb_user":            resourceIboxSmbUser(),
+			"ibox_smb_group":           resourceIboxSmbGroup(),
+			"ibox_treeq":               resourceIboxTreeq(),
+			"ibox_nfs_user_quota":      resourceIboxNfsQuota("user"),
+			"ibox_nfs_group_quota":     resourceIboxNfsQuota("group"),
+			"ibox_user":                resourceIboxUser(),
+			"ibox_ldap_config":         resourceIboxLdapConfig(),
+			"ibox_ldap_role_mapping":   resourceIboxLdapRoleMapping(),
+			"ibox_notification_target": resourceIboxNotificationTarget(),
+			"ibox_notification_rule":   resourceIboxNotificationRule(),
+		}),
+
 		ConfigureFunc: providerConfigure,
 	}
 }
@@ -45,7 +176,34 @@ func providerConfigure(data *schema.ResourceData) (interface{}, error) {
 		Username: data.Get("username").(string),
 		Password: data.Get("password").(string),
 		Hostname: data.Get("hostname").(string),
+
+		RequestsPerSecond:     data.Get("requests_per_second").(float64),
+		Burst:                 data.Get("burst").(int),
+		MaxConcurrentRequests: data.Get("max_concurrent_requests").(int),
+
+		LogHttpBodies:  data.Get("log_http_bodies").(bool),
+		RecordEventIds: data.Get("record_event_ids").(bool),
+
+		Profile:          data.Get("profile").(string),
+		CredentialsFile:  data.Get("credentials_file").(string),
+		CredentialHelper: data.Get("credential_helper").(string),
+	}
+
+	if v, ok := data.GetOk("default_metadata"); ok && v.([]interface{})[0] != nil {
+		config.DefaultMetadata = expandDefaultMetadata(v.([]interface{})[0].(map[string]interface{}))
+	}
+
+	for _, raw := range data.Get("system").([]interface{}) {
+		systemmap := raw.(map[string]interface{})
+		system := SystemConfig{
+			Name:     systemmap["name"].(string),
+			Hostname: systemmap["hostname"].(string),
+			Username: systemmap["username"].(string),
+			Password: systemmap["password"].(string),
+			Profile:  systemmap["profile"].(string),
+		}
+		config.Systems = append(config.Systems, system)
 	}
 
-	return config.Client()
+	return config.Clients()
 }
@@ -0,0 +1,89 @@
+package ibox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const redacted = "******"
+
+// Headers which are never written to the debug log
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// JSON keys are masked when their name contains one of the following words
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"passphrase",
+	"private_key",
+	"community",
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of the header with sensitive values masked
+func redactHeader(header http.Header) http.Header {
+	out := make(http.Header, len(header))
+	for k, v := range header {
+		out[k] = v
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out.Set(k, redacted)
+		}
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, nested := range value {
+			if isSensitiveKey(k) {
+				value[k] = redacted
+			} else {
+				value[k] = redactValue(nested)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = redactValue(nested)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// redactBody masks sensitive JSON fields, bodies which are not JSON are not logged at all
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("<%d bytes of non JSON body omitted>", len(body))
+	}
+	out, err := json.MarshalIndent(redactValue(v), "", "    ")
+	if err != nil {
+		return fmt.Sprintf("<%d bytes of body omitted>", len(body))
+	}
+	return string(out)
+}
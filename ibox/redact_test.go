@@ -0,0 +1,123 @@
+package ibox
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	cases := map[string]bool{
+		"password":                       true,
+		"Password":                       true,
+		"bind_password":                  true,
+		"security_chap_inbound_secret":   true,
+		"security_chap_outbound_secret":  true,
+		"auth_token":                     true,
+		"ssh_private_key":                true,
+		"community":                      true,
+		"name":                           false,
+		"security_chap_inbound_username": false,
+		"security_method":                false,
+	}
+	for key, expected := range cases {
+		if got := isSensitiveKey(key); got != expected {
+			t.Errorf("isSensitiveKey(%q) = %v, expected %v", key, got, expected)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		hidden   []string
+		visible  []string
+		expected string
+	}{
+		{
+			name:     "empty",
+			body:     "",
+			expected: "",
+		},
+		{
+			name:    "password",
+			body:    `{"name": "admin", "password": "hunter2"}`,
+			hidden:  []string{"hunter2"},
+			visible: []string{`"name": "admin"`, `"password": "` + redacted + `"`},
+		},
+		{
+			name:    "chap secrets",
+			body:    `{"security_method": "MUTUAL_CHAP", "security_chap_inbound_secret": "inbound-secret-1", "security_chap_outbound_secret": "outbound-secret1", "security_chap_inbound_username": "iuser"}`,
+			hidden:  []string{"inbound-secret-1", "outbound-secret1"},
+			visible: []string{`"security_method": "MUTUAL_CHAP"`, `"security_chap_inbound_username": "iuser"`},
+		},
+		{
+			name:    "nested map",
+			body:    `{"result": {"host": {"name": "h1", "security_chap_inbound_secret": "nested-secret"}}}`,
+			hidden:  []string{"nested-secret"},
+			visible: []string{`"name": "h1"`},
+		},
+		{
+			name:    "array of maps",
+			body:    `[{"name": "u1", "password": "first-pass"}, {"name": "u2", "bind_password": "second-pass"}]`,
+			hidden:  []string{"first-pass", "second-pass"},
+			visible: []string{`"name": "u1"`, `"name": "u2"`},
+		},
+		{
+			name:    "sensitive key with object value",
+			body:    `{"secret": {"value": "deep"}, "tokens": ["t1", "t2"]}`,
+			hidden:  []string{"deep", "t1", "t2"},
+			visible: []string{redacted},
+		},
+		{
+			name:     "non JSON",
+			body:     "password=hunter2&user=admin",
+			expected: "<27 bytes of non JSON body omitted>",
+		},
+	}
+	for _, c := range cases {
+		got := redactBody([]byte(c.body))
+		if c.expected != "" || c.body == "" {
+			if got != c.expected {
+				t.Errorf("%v: expected %q, got %q", c.name, c.expected, got)
+			}
+			continue
+		}
+		for _, s := range c.hidden {
+			if strings.Contains(got, s) {
+				t.Errorf("%v: %q is not masked in:\n%v", c.name, s, got)
+			}
+		}
+		for _, s := range c.visible {
+			if !strings.Contains(got, s) {
+				t.Errorf("%v: %q is missing from:\n%v", c.name, s, got)
+			}
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Basic YWRtaW46MTIzNDU2")
+	header.Set("Cookie", "JSESSIONID=abc")
+	header.Set("Set-Cookie", "JSESSIONID=def")
+	header.Set("Content-Type", "application/json")
+
+	out := redactHeader(header)
+
+	for _, k := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if got := out.Get(k); got != redacted {
+			t.Errorf("%v header is not masked: %q", k, got)
+		}
+	}
+	if got := out.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type header changed: %q", got)
+	}
+	if got := header.Get("Authorization"); got != "Basic YWRtaW46MTIzNDU2" {
+		t.Errorf("the original header was modified: %q", got)
+	}
+	if _, ok := redactHeader(http.Header{})["Authorization"]; ok {
+		t.Errorf("a missing Authorization header was added")
+	}
+}
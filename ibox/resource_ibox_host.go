@@ -2,12 +2,40 @@ package ibox
 
 import (
 	// "log"
+	"bytes"
 	"fmt"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
+	"sort"
 	"strconv"
 )
 
+// Host types known by InfiniBox, they change the SCSI behaviour of the array towards the host
+var hostTypes = []string{
+	"default",
+	"vmware",
+	"linux",
+	"windows",
+	"aix",
+	"hpux",
+	"solaris",
+	"zvm",
+}
+
+// Port types, NVME ports are NVMe over Fabrics (NVMe/TCP) hosts identified by their NQN
+var portTypes = []string{
+	"FC",
+	"ISCSI",
+	"NVME",
+}
+
+var sanClientTypes = []string{
+	"HOST",
+	"CLUSTER",
+}
+
 func resourceIboxHost() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceIboxHostCreate,
@@ -15,15 +43,33 @@ func resourceIboxHost() *schema.Resource {
 		Update: resourceIboxHostUpdate,
 		Delete: resourceIboxHostDelete,
 
+		CustomizeDiff: resourceIboxHostCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
 
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"host_type": {
+				Description:  "Host operating system type, e.g. vmware, linux, windows",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInList(hostTypes, false),
+			},
+			"san_client_type": {
+				Description:  "SAN client type of the host",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInList(sanClientTypes, false),
+			},
 			"security_method": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Default:  "NONE",
 				ValidateFunc: validateStringInList([]string{
 					"NONE",
 					"CHAP",
@@ -35,8 +81,11 @@ func resourceIboxHost() *schema.Resource {
 				Optional: true,
 			},
 			"security_chap_inbound_secret": {
+				Description:  "CHAP inbound secret, only its SHA256 hash is kept in the state",
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
+				StateFunc:    hashSecret,
 				ValidateFunc: validateStringLenghtInRange(14, 255),
 			},
 			"security_chap_outbound_username": {
@@ -44,33 +93,37 @@ func resourceIboxHost() *schema.Resource {
 				Optional: true,
 			},
 			"security_chap_outbound_secret": {
+				Description:  "CHAP outbound secret, only its SHA256 hash is kept in the state",
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
+				StateFunc:    hashSecret,
 				ValidateFunc: validateStringLenghtInRange(14, 255),
 			},
+			"metadata":     metadataSchema(),
+			"metadata_all": metadataAllSchema(),
 			"id": {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
 			"ports": {
-				Description: "FC or ISCSI port",
-				Type:        schema.TypeList,
+				Description: "FC, ISCSI or NVME port, do not combine with ibox_host_port resources of the same host",
+				Type:        schema.TypeSet,
 				Optional:    true,
+				Computed:    true,
+				Set:         hostPortHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"address": {
-							Description: "IQN for ISCSI or WWN address for FC",
+							Description: "IQN for ISCSI, NQN for NVME or WWN address for FC, WWNs are normalized to the canonical form e.g. 21100024ff913bff",
 							Optional:    true,
 							Type:        schema.TypeString,
 						},
 						"type": {
-							Description: "Port type FC or ISCSI",
-							Optional:    true,
-							Type:        schema.TypeString,
-							ValidateFunc: validateStringInList([]string{
-								"FC",
-								"ISCSI",
-							}, false),
+							Description:  "Port type FC, ISCSI or NVME",
+							Optional:     true,
+							Type:         schema.TypeString,
+							ValidateFunc: validateStringInList(portTypes, false),
 						},
 						"host_id": {
 							Type:     schema.TypeInt,
@@ -84,12 +137,21 @@ func resourceIboxHost() *schema.Resource {
 }
 
 func resourceIboxHostCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newHost := Host{
 		Name: d.Get("name").(string),
 	}
 
+	if v, ok := d.GetOk("host_type"); ok {
+		newHost.Host_type = v.(string)
+	}
+	if v, ok := d.GetOk("san_client_type"); ok {
+		newHost.San_client_type = v.(string)
+	}
 	if v, ok := d.GetOk("security_method"); ok {
 		newHost.Security_method = v.(string)
 	}
@@ -114,10 +176,10 @@ func resourceIboxHostCreate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	} else {
 		d.SetId(strconv.Itoa(host.Id))
-		portsRaw := d.Get("ports").([]interface{})
+		portsRaw := d.Get("ports").(*schema.Set).List()
 		for _, port := range portsRaw {
 			portmap := port.(map[string]interface{})
-			portAdd := Port{Address: portmap["address"].(string), Type: portmap["type"].(string)}
+			portAdd := Port{Address: normalizeWWN(portmap["address"].(string)), Type: portmap["type"].(string)}
 			// Trying to add port to the new host, if one of the defined ports cannot be added, the new created host will be deleted.
 			_, err := client.CreatePort(portAdd, host.Id)
 			if err != nil {
@@ -126,13 +188,19 @@ func resourceIboxHostCreate(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
+		if err := createMetadata(d, client, host.Id); err != nil {
+			return err
+		}
 		resourceIboxHostRead(d, meta)
 		return nil
 	}
 }
 
 func resourceIboxHostRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	host_id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -153,24 +221,43 @@ func resourceIboxHostRead(d *schema.ResourceData, meta interface{}) error {
 	for _, port := range portsRawNew {
 
 		portToSave := make(map[string]interface{})
-		portToSave["address"] = port.Address
+		portToSave["address"] = normalizeWWN(port.Address)
 		portToSave["type"] = port.Type
 		portToSave["host_id"] = port.Host_id
 		ports = append(ports, portToSave)
 	}
 
+	// The API never returns CHAP secrets, only whether they are set. If a secret
+	// was cleared out of band forget its hash, so the next plan sets it again.
+	if !host.Security_chap_has_inbound_secret && d.Get("security_chap_inbound_secret").(string) != "" {
+		log.Printf("[WARN] CHAP inbound secret of host id: %v was cleared out of band", host_id)
+		d.Set("security_chap_inbound_secret", "")
+	}
+	if !host.Security_chap_has_outbound_secret && d.Get("security_chap_outbound_secret").(string) != "" {
+		log.Printf("[WARN] CHAP outbound secret of host id: %v was cleared out of band", host_id)
+		d.Set("security_chap_outbound_secret", "")
+	}
+	d.Set("security_method", host.Security_method)
+	d.Set("security_chap_inbound_username", host.Security_chap_inbound_username)
+	d.Set("security_chap_outbound_username", host.Security_chap_outbound_username)
+
 	log.Printf("[INFO] READER Setting ports %+v to tfstate", ports)
 	err = d.Set("ports", ports)
 	if err != nil {
 		return fmt.Errorf("[ERROR] Error setting ports: %#v", err)
 	}
 	d.Set("name", host.Name)
+	d.Set("host_type", host.Host_type)
+	d.Set("san_client_type", host.San_client_type)
 
-	return nil
+	return readMetadata(d, client, host_id)
 }
 
 func resourceIboxHostDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	host_id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -186,38 +273,20 @@ func resourceIboxHostDelete(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceIboxHostUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	host_id, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return fmt.Errorf(err.Error())
 	}
 
-	if d.HasChange("name") {
-		hostToUpdate := new(Host)
-		hostToUpdate.Name = d.Get("name").(string)
-		log.Printf("[DEBUG] Host to update: %v", hostToUpdate)
-		host, err := client.UpdateHost(*hostToUpdate, host_id)
-		if err != nil {
-			return err
-		}
-		log.Printf("[DEBUG] Updated host: %v", host)
-		d.SetId(strconv.Itoa(host.Id))
-	}
-
-	if d.HasChange("security_method") || d.HasChange("security_chap_inbound_username") || d.HasChange("security_chap_inbound_secret") || d.HasChange("security_chap_outbound_username") || d.HasChange("security_chap_outbound_secret") {
-		var hostToUpdate Host
-		_, newv := d.GetChange("security_method")
-		if newv == "" {
-			hostToUpdate.Security_method = "NONE"
-		}
-		hostToUpdate.Security_chap_inbound_username = d.Get("security_chap_inbound_username").(string)
-		hostToUpdate.Security_chap_inbound_secret = d.Get("security_chap_inbound_secret").(string)
-		hostToUpdate.Security_chap_outbound_username = d.Get("security_chap_outbound_username").(string)
-		hostToUpdate.Security_chap_outbound_secret = d.Get("security_chap_outbound_secret").(string)
-
-		log.Printf("[DEBUG] Host to update: %v", hostToUpdate)
-		_, err := client.UpdateHost(hostToUpdate, host_id)
+	m := hostUpdateFields(d)
+	if len(m) > 0 {
+		log.Printf("[DEBUG] Updating keys: %v of host id: %v", hostUpdateKeys(m), host_id)
+		_, err := client.UpdateHost(m, host_id)
 		if err != nil {
 			return err
 		}
@@ -225,25 +294,104 @@ func resourceIboxHostUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	if d.HasChange("ports") {
 		old_value, new_value := d.GetChange("ports")
-		portsRawOld := old_value.([]interface{})
-		portsRawNew := new_value.([]interface{})
-		for _, port := range portsRawOld {
+		oldPorts := old_value.(*schema.Set)
+		newPorts := new_value.(*schema.Set)
+		// New paths are added before the old ones are removed, so the host never loses all of its paths
+		for _, port := range newPorts.Difference(oldPorts).List() {
 			portmap := port.(map[string]interface{})
-			portToDelete := Port{Address: portmap["address"].(string), Type: portmap["type"].(string)}
-			_, err := client.DeletePort(host_id, portToDelete)
+			portToAdd := Port{Address: normalizeWWN(portmap["address"].(string)), Type: portmap["type"].(string)}
+			_, err := client.CreatePort(portToAdd, host_id)
 			if err != nil {
 				return err
 			}
 		}
-		for _, port := range portsRawNew {
+		for _, port := range oldPorts.Difference(newPorts).List() {
 			portmap := port.(map[string]interface{})
-			portToAdd := Port{Address: portmap["address"].(string), Type: portmap["type"].(string)}
-			_, err := client.CreatePort(portToAdd, host_id)
+			portToDelete := Port{Address: normalizeWWN(portmap["address"].(string)), Type: portmap["type"].(string)}
+			_, err := client.DeletePort(host_id, portToDelete)
 			if err != nil {
 				return err
 			}
 		}
 	}
+
+	if err := updateMetadata(d, client, host_id); err != nil {
+		return err
+	}
 	resourceIboxHostRead(d, meta)
 	return nil
 }
+
+// changeGetter is the part of schema.ResourceData needed to collect the changed keys of a resource
+type changeGetter interface {
+	HasChange(key string) bool
+	Get(key string) interface{}
+}
+
+// hostUpdateFields returns the changed host settings. CHAP secrets are only sent when they changed,
+// the state holds their hash and sending it back would replace the secret on the array.
+func hostUpdateFields(d changeGetter) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, k := range []string{
+		"name",
+		"host_type",
+		"san_client_type",
+		"security_method",
+		"security_chap_inbound_username",
+		"security_chap_inbound_secret",
+		"security_chap_outbound_username",
+		"security_chap_outbound_secret",
+	} {
+		if d.HasChange(k) {
+			m[k] = d.Get(k)
+		}
+	}
+	if v, ok := m["security_method"]; ok && v == "" {
+		m["security_method"] = "NONE"
+	}
+	return m
+}
+
+// hostUpdateKeys lists the keys of an update without their values, which can be secrets
+func hostUpdateKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hostPortHash identifies a port by its type and address, the computed host_id is ignored
+func hostPortHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["type"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", normalizeWWN(m["address"].(string))))
+	return hashcode.String(buf.String())
+}
+
+// resourceIboxHostCustomizeDiff validates the port addresses according to their type
+func resourceIboxHostCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for _, port := range d.Get("ports").(*schema.Set).List() {
+		portmap := port.(map[string]interface{})
+		address := portmap["address"].(string)
+		if address == "" || address == config.UnknownVariableValue {
+			continue
+		}
+		if err := validatePortAddress(portmap["type"].(string), address); err != nil {
+			return err
+		}
+	}
+	return customizeMetadataDiff(d, meta)
+}
+
+// hostIsNvme reports whether the host is an NVMe over Fabrics host
+func hostIsNvme(host *Host) bool {
+	for _, port := range host.Ports {
+		if port.Type == "NVME" {
+			return true
+		}
+	}
+	return false
+}
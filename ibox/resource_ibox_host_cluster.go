@@ -15,18 +15,37 @@ func resourceIboxHostCluster() *schema.Resource {
 		Delete: resourceIboxHostClusterDelete,
 		Update: resourceIboxHostClusterUpdate,
 
+		CustomizeDiff: customizeMetadataDiff,
+
 		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"host_type": {
+				Description:  "Operating system type of the cluster hosts, e.g. vmware, linux, windows",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInList(hostTypes, false),
+			},
+			"san_client_type": {
+				Description:  "SAN client type of the host cluster",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInList(sanClientTypes, false),
+			},
 			"hosts": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeInt,
 				},
 				Optional: true,
 			},
+			"metadata":     metadataSchema(),
+			"metadata_all": metadataAllSchema(),
 			"id": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -36,18 +55,27 @@ func resourceIboxHostCluster() *schema.Resource {
 }
 
 func resourceIboxHostClusterCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newhostCluster := Host_cluster{
 		Name: d.Get("name").(string),
 	}
+	if v, ok := d.GetOk("host_type"); ok {
+		newhostCluster.Host_type = v.(string)
+	}
+	if v, ok := d.GetOk("san_client_type"); ok {
+		newhostCluster.San_client_type = v.(string)
+	}
 
 	hostCluster, err := client.CreateHostCluster(newhostCluster)
 	if err != nil {
 		return err
 	}
 
-	hosts := d.Get("hosts").([]interface{})
+	hosts := d.Get("hosts").(*schema.Set).List()
 	for _, host_id := range hosts {
 		log.Printf("[DEBUG] configured host_id: %v in host_cluster config", host_id)
 		client.AddHostToHostCluster(hostCluster.Id, host_id.(int))
@@ -57,11 +85,14 @@ func resourceIboxHostClusterCreate(d *schema.ResourceData, meta interface{}) err
 	// d.Set("host_id", lun.Host_id)
 	// d.Set("host_cluster_id", lun.Host_cluster_id)
 
-	return nil
+	return createMetadata(d, client, hostCluster.Id)
 }
 
 func resourceIboxHostClusterRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	host_cluster_id := d.Get("id").(int)
 
@@ -72,15 +103,29 @@ func resourceIboxHostClusterRead(d *schema.ResourceData, meta interface{}) error
 	if host_cluster == nil {
 		log.Printf("[WARN] Probably the host cluster was deleted out of band, removing it from state")
 		d.SetId("")
+		return nil
 	}
-	return nil
+	hosts := make([]interface{}, 0, len(host_cluster.Hosts))
+	for _, host := range host_cluster.Hosts {
+		hosts = append(hosts, host.Id)
+	}
+	if err := d.Set("hosts", hosts); err != nil {
+		return err
+	}
+	d.Set("host_type", host_cluster.Host_type)
+	d.Set("san_client_type", host_cluster.San_client_type)
+
+	return readMetadata(d, client, host_cluster_id)
 }
 
 func resourceIboxHostClusterDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	host_cluster_id := d.Get("id").(int)
-	err := client.DeleteHostCluster(host_cluster_id)
+	err = client.DeleteHostCluster(host_cluster_id)
 	if err != nil {
 		return err
 	}
@@ -88,38 +133,46 @@ func resourceIboxHostClusterDelete(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceIboxHostClusterUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 	// d.Partial(true)
 
 	host_cluster_id := d.Get("id").(int)
 	if d.HasChange("hosts") {
 		oldv, newv := d.GetChange("hosts")
-		// o, n := d.GetChange("hosts.#")
-		oldList := oldv.([]interface{})
+		oldSet := oldv.(*schema.Set)
+		newSet := newv.(*schema.Set)
 
-		for _, host_id := range oldList {
-			log.Printf("[INFO] Going to remove the following host id: %v to cluster id: %v", host_id, host_cluster_id)
-			_, err := client.RemoveHostFromHostCluster(host_cluster_id, host_id.(int))
+		// Hosts are added before the removed ones leave, the other hosts of the cluster keep their LUNs
+		for _, host_id := range newSet.Difference(oldSet).List() {
+			log.Printf("[INFO] Going to add the following host id: %v to cluster id: %v", host_id, host_cluster_id)
+			_, err := client.AddHostToHostCluster(host_cluster_id, host_id.(int))
 			if err != nil {
 				return err
 			}
 		}
 
-		newList := newv.([]interface{})
-
-		for _, host_id := range newList {
-			log.Printf("[INFO] Going to add the following host id: %v to cluster id: %v", host_id, host_cluster_id)
-			_, err := client.AddHostToHostCluster(host_cluster_id, host_id.(int))
+		for _, host_id := range oldSet.Difference(newSet).List() {
+			log.Printf("[INFO] Going to remove the following host id: %v from cluster id: %v", host_id, host_cluster_id)
+			_, err := client.RemoveHostFromHostCluster(host_cluster_id, host_id.(int))
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	if d.HasChange("name") {
+	if d.HasChange("name") || d.HasChange("host_type") || d.HasChange("san_client_type") {
 		var m map[string]interface{}
 		m = make(map[string]interface{})
 		m["name"] = d.Get("name").(string)
+		if v, ok := d.GetOk("host_type"); ok {
+			m["host_type"] = v.(string)
+		}
+		if v, ok := d.GetOk("san_client_type"); ok {
+			m["san_client_type"] = v.(string)
+		}
 
 		_, err := client.UpdateHostCluster(m, host_cluster_id)
 		if err != nil {
@@ -127,6 +180,9 @@ func resourceIboxHostClusterUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 
 	}
-	log.Printf("[DEBUG] %v", client)
+
+	if err := updateMetadata(d, client, host_cluster_id); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,48 @@
+package ibox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceIboxHostClusterUpdateHosts(t *testing.T) {
+	api, client := newFakeApi(t, map[string]string{
+		"POST /clusters/2/hosts": `{"id": 2, "name": "c1"}`,
+		"GET /metadata/2":        `[]`,
+	})
+	defer api.Close()
+	api.resultFunc = func(r fakeRequest) (string, bool) {
+		if r.Method == "DELETE" && strings.HasPrefix(r.Path, "/clusters/2/hosts/") {
+			return `{"id": 2, "name": "c1"}`, true
+		}
+		return "", false
+	}
+
+	r := resourceIboxHostCluster()
+	state := testResourceState(t, r, 2, map[string]interface{}{
+		"name":  "c1",
+		"hosts": []interface{}{1, 2},
+	})
+	testApplyResource(t, r, client, state, map[string]interface{}{
+		"name":  "c1",
+		"hosts": []interface{}{2, 3, 4},
+	})
+
+	requests := changeRequests(api, "/clusters/2/hosts")
+	if len(requests) != 3 {
+		t.Fatalf("expected 2 additions and 1 removal, got: %v", requests)
+	}
+	added := map[float64]bool{}
+	for _, request := range requests[:2] {
+		if request.Method != "POST" {
+			t.Fatalf("expected the additions before the removals, got: %v", requests)
+		}
+		added[request.Body["id"].(float64)] = true
+	}
+	if !added[3] || !added[4] {
+		t.Errorf("expected hosts 3 and 4 to be added, got: %v", requests)
+	}
+	if requests[2].Method != "DELETE" || requests[2].Path != "/clusters/2/hosts/1" {
+		t.Errorf("expected only host 1 to be removed last, got: %v", requests)
+	}
+}
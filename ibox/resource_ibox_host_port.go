@@ -0,0 +1,157 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+	"strings"
+)
+
+func resourceIboxHostPort() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxHostPortCreate,
+		Read:   resourceIboxHostPortRead,
+		Delete: resourceIboxHostPortDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceIboxHostPortImport,
+		},
+
+		CustomizeDiff: resourceIboxHostPortCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"host_id": {
+				Description: "Host the port belongs to, changing it moves the port to another host",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"type": {
+				Description:  "Port type FC, ISCSI or NVME",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateStringInList(portTypes, false),
+			},
+			"address": {
+				Description: "IQN for ISCSI, NQN for NVME or WWN address for FC, WWNs are stored in the canonical form e.g. 21100024ff913bff",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				StateFunc:   normalizePortAddress,
+			},
+		},
+	}
+}
+
+// The port id is host_id/type/address, IQNs contain colons so they cannot be used as separator
+func hostPortId(host_id int, port_type string, address string) string {
+	return strconv.Itoa(host_id) + "/" + port_type + "/" + address
+}
+
+func parseHostPortId(id string) (int, string, string, error) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("[ERROR] host port id: %v is not in the host_id/type/address format", id)
+	}
+	host_id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("[ERROR] host port id: %v has an invalid host_id: %v", id, err)
+	}
+	return host_id, parts[1], parts[2], nil
+}
+
+func resourceIboxHostPortCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	host_id := d.Get("host_id").(int)
+	newPort := Port{
+		Address: normalizeWWN(d.Get("address").(string)),
+		Type:    d.Get("type").(string),
+	}
+
+	_, err = client.CreatePort(newPort, host_id)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(hostPortId(host_id, newPort.Type, newPort.Address))
+
+	return resourceIboxHostPortRead(d, meta)
+}
+
+func resourceIboxHostPortRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	host_id, port_type, address, err := parseHostPortId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	port, err := client.ReadPort(host_id, address)
+	if err != nil {
+		return err
+	}
+	if port == nil {
+		log.Printf("[WARN] Probably the port was removed out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("host_id", host_id)
+	d.Set("type", port_type)
+	d.Set("address", normalizeWWN(port.Address))
+
+	return nil
+}
+
+func resourceIboxHostPortDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	host_id, port_type, address, err := parseHostPortId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeletePort(host_id, Port{Address: address, Type: port_type})
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceIboxHostPortImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := importSystem(d, meta); err != nil {
+		return nil, err
+	}
+	host_id, port_type, address, err := parseHostPortId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("host_id", host_id)
+	d.Set("type", port_type)
+	d.Set("address", normalizeWWN(address))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceIboxHostPortCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	address := d.Get("address").(string)
+	if address == "" || address == config.UnknownVariableValue {
+		return nil
+	}
+	return validatePortAddress(d.Get("type").(string), address)
+}
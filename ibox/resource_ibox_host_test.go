@@ -0,0 +1,176 @@
+package ibox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testHostResult = `{
+	"id": 1,
+	"name": "h1",
+	"security_method": "CHAP",
+	"security_chap_inbound_username": "new-user",
+	"security_chap_has_inbound_secret": true,
+	"security_chap_outbound_username": "out-user",
+	"security_chap_has_outbound_secret": true
+}`
+
+func testHostState() *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                              "1",
+			"name":                            "h1",
+			"security_method":                 "CHAP",
+			"security_chap_inbound_username":  "old-user",
+			"security_chap_inbound_secret":    hashSecret("inbound-secret-1"),
+			"security_chap_outbound_username": "out-user",
+			"security_chap_outbound_secret":   hashSecret("outbound-secret1"),
+			"ports.#":                         "0",
+			"metadata.%":                      "0",
+			"metadata_all.%":                  "0",
+		},
+	}
+}
+
+func testApplyHost(t *testing.T, raw map[string]interface{}) *fakeApi {
+	api, client := newFakeApi(t, map[string]string{
+		"GET /hosts/1":    testHostResult,
+		"PUT /hosts/1":    testHostResult,
+		"GET /metadata/1": `[]`,
+	})
+	meta := &ClientPool{Default: client}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := resourceIboxHost()
+	state := testHostState()
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, meta); err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestResourceIboxHostUpdateChapKeepsSecrets(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"username": {
+			"name":                            "h1",
+			"security_method":                 "CHAP",
+			"security_chap_inbound_username":  "new-user",
+			"security_chap_inbound_secret":    "inbound-secret-1",
+			"security_chap_outbound_username": "out-user",
+			"security_chap_outbound_secret":   "outbound-secret1",
+		},
+		"method": {
+			"name":                            "h1",
+			"security_method":                 "MUTUAL_CHAP",
+			"security_chap_inbound_username":  "old-user",
+			"security_chap_inbound_secret":    "inbound-secret-1",
+			"security_chap_outbound_username": "out-user",
+			"security_chap_outbound_secret":   "outbound-secret1",
+		},
+	}
+	for name, raw := range cases {
+		api := testApplyHost(t, raw)
+		puts := api.requestsTo("PUT", "/hosts/1")
+		api.Close()
+		if len(puts) != 1 {
+			t.Fatalf("%v: expected one host update, got: %v", name, puts)
+		}
+		for _, k := range []string{"security_chap_inbound_secret", "security_chap_outbound_secret"} {
+			if v, ok := puts[0].Body[k]; ok {
+				t.Errorf("%v: %v was sent although it did not change: %v", name, k, v)
+			}
+		}
+	}
+}
+
+func TestResourceIboxHostUpdateChapSendsChangedSecret(t *testing.T) {
+	api := testApplyHost(t, map[string]interface{}{
+		"name":                            "h1",
+		"security_method":                 "CHAP",
+		"security_chap_inbound_username":  "old-user",
+		"security_chap_inbound_secret":    "inbound-secret-2",
+		"security_chap_outbound_username": "out-user",
+		"security_chap_outbound_secret":   "outbound-secret1",
+	})
+	defer api.Close()
+
+	puts := api.requestsTo("PUT", "/hosts/1")
+	if len(puts) != 1 {
+		t.Fatalf("expected one host update, got: %v", puts)
+	}
+	if v := puts[0].Body["security_chap_inbound_secret"]; v != "inbound-secret-2" {
+		t.Errorf("expected the new inbound secret to be sent, got: %v", v)
+	}
+	if v, ok := puts[0].Body["security_chap_outbound_secret"]; ok {
+		t.Errorf("unchanged outbound secret was sent: %v", v)
+	}
+}
+
+func TestResourceIboxHostUpdatePorts(t *testing.T) {
+	api, client := newFakeApi(t, map[string]string{
+		"GET /hosts/1":        testHostResult,
+		"POST /hosts/1/ports": `{"address": "", "type": "FC"}`,
+		"GET /metadata/1":     `[]`,
+	})
+	defer api.Close()
+	api.resultFunc = func(r fakeRequest) (string, bool) {
+		if r.Method == "DELETE" && strings.HasPrefix(r.Path, "/hosts/1/ports/") {
+			return `[{"type": "FC", "address": "21100024ff913b00"}]`, true
+		}
+		return "", false
+	}
+
+	host := func(ports ...map[string]interface{}) map[string]interface{} {
+		raw := map[string]interface{}{
+			"name":                            "h1",
+			"security_method":                 "CHAP",
+			"security_chap_inbound_username":  "old-user",
+			"security_chap_inbound_secret":    "inbound-secret-1",
+			"security_chap_outbound_username": "out-user",
+			"security_chap_outbound_secret":   "outbound-secret1",
+		}
+		list := make([]interface{}, 0, len(ports))
+		for _, port := range ports {
+			list = append(list, port)
+		}
+		raw["ports"] = list
+		return raw
+	}
+	kept := map[string]interface{}{"type": "FC", "address": "21100024ff913bff"}
+	removed := map[string]interface{}{"type": "FC", "address": "21100024ff913b00"}
+	added_fc := map[string]interface{}{"type": "FC", "address": "21100024ff913b01"}
+	added_iscsi := map[string]interface{}{"type": "ISCSI", "address": "iqn.1994-05.com.redhat:1a2b3c"}
+
+	r := resourceIboxHost()
+	state := testResourceState(t, r, 1, host(kept, removed))
+	testApplyResource(t, r, client, state, host(kept, added_fc, added_iscsi))
+
+	requests := changeRequests(api, "/hosts/1/ports")
+	if len(requests) != 3 {
+		t.Fatalf("expected 2 additions and 1 removal, got: %v", requests)
+	}
+	added := map[string]bool{}
+	for _, request := range requests[:2] {
+		if request.Method != "POST" {
+			t.Fatalf("expected the additions before the removals, got: %v", requests)
+		}
+		added[request.Body["type"].(string)+"/"+request.Body["address"].(string)] = true
+	}
+	if !added["FC/21100024ff913b01"] || !added["ISCSI/iqn.1994-05.com.redhat:1a2b3c"] {
+		t.Errorf("expected only the new ports to be added, got: %v", requests)
+	}
+	if requests[2].Method != "DELETE" || requests[2].Path != "/hosts/1/ports/FC/21100024ff913b00" {
+		t.Errorf("expected only the removed port to be deleted last, got: %v", requests)
+	}
+}
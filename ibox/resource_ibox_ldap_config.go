@@ -0,0 +1,296 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxLdapConfig() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxLdapConfigCreate,
+		Read:   resourceIboxLdapConfigRead,
+		Update: resourceIboxLdapConfigUpdate,
+		Delete: resourceIboxLdapConfigDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"repository_type": {
+				Description: "Directory type ActiveDirectory or LDAP",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "ActiveDirectory",
+				ValidateFunc: validateStringInList([]string{
+					"ActiveDirectory",
+					"LDAP",
+				}, false),
+			},
+			"domain_name": {
+				Description: "Active Directory domain, its domain controllers are discovered with DNS when servers is not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"servers": {
+				Description: "Directory servers in the order they are tried",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ldap_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntegerInRange(1, 65535),
+			},
+			"use_ldaps": {
+				Description: "Connect to the servers with LDAP over TLS",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"base_dn": {
+				Description: "Base DN of the user and group searches e.g. dc=corp,dc=example,dc=com",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"bind_username": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"bind_password": {
+				Description: "Password of the bind user, only its SHA256 hash is kept in the state",
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"schema": {
+				Description: "Object classes and attributes of users and groups, the array defaults match Active Directory",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_class": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"username_attribute": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"group_class": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"groupname_attribute": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"group_memberof_attribute": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func expandLdapSchema(d *schema.ResourceData) *Ldap_schema {
+	ldapSchema := &Ldap_schema{
+		User_search_base_dn:  d.Get("base_dn").(string),
+		Group_search_base_dn: d.Get("base_dn").(string),
+	}
+	if list := d.Get("schema").([]interface{}); len(list) > 0 && list[0] != nil {
+		m := list[0].(map[string]interface{})
+		ldapSchema.User_class = m["user_class"].(string)
+		ldapSchema.Username_attribute = m["username_attribute"].(string)
+		ldapSchema.Group_class = m["group_class"].(string)
+		ldapSchema.Groupname_attribute = m["groupname_attribute"].(string)
+		ldapSchema.Group_memberof_attribute = m["group_memberof_attribute"].(string)
+	}
+	return ldapSchema
+}
+
+func resourceIboxLdapConfigCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newConfig := Ldap_config{
+		Name:              d.Get("name").(string),
+		Repository_type:   d.Get("repository_type").(string),
+		Domain_name:       d.Get("domain_name").(string),
+		Servers:           expandStringList(d.Get("servers").([]interface{})),
+		Ldap_port:         d.Get("ldap_port").(int),
+		Use_ldaps:         d.Get("use_ldaps").(bool),
+		Bind_username:     d.Get("bind_username").(string),
+		Bind_password:     d.Get("bind_password").(string),
+		Schema_definition: expandLdapSchema(d),
+	}
+
+	config, err := client.CreateLdapConfig(newConfig)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(config.Id))
+
+	// A failed test leaves the config tainted, so it is recreated on the next apply
+	if err := client.TestLdapConfig(config.Id); err != nil {
+		return err
+	}
+
+	return resourceIboxLdapConfigRead(d, meta)
+}
+
+func resourceIboxLdapConfigRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	config_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	config, err := client.ReadLdapConfig(config_id)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		log.Printf("[WARN] Probably the ldap config was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	err = setReadFields(d, map[string]interface{}{
+		"name":            config.Name,
+		"repository_type": config.Repository_type,
+		"domain_name":     config.Domain_name,
+		"ldap_port":       config.Ldap_port,
+		"use_ldaps":       config.Use_ldaps,
+		"bind_username":   config.Bind_username,
+		"servers":         config.Servers,
+	})
+	if err != nil {
+		return err
+	}
+	if config.Schema_definition != nil {
+		d.Set("base_dn", config.Schema_definition.User_search_base_dn)
+		ldapSchema := []map[string]interface{}{
+			{
+				"user_class":               config.Schema_definition.User_class,
+				"username_attribute":       config.Schema_definition.Username_attribute,
+				"group_class":              config.Schema_definition.Group_class,
+				"groupname_attribute":      config.Schema_definition.Groupname_attribute,
+				"group_memberof_attribute": config.Schema_definition.Group_memberof_attribute,
+			},
+		}
+		if err := d.Set("schema", ldapSchema); err != nil {
+			return fmt.Errorf("[ERROR] Error setting schema: %#v", err)
+		}
+	}
+
+	return nil
+}
+
+func resourceIboxLdapConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	config_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	// The connection test runs after the update was applied, in partial mode a failed test keeps the
+	// previous values in the state so the next plan retries the update and the test
+	d.Partial(true)
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("domain_name") {
+		m["domain_name"] = d.Get("domain_name").(string)
+	}
+	if d.HasChange("servers") {
+		m["servers"] = expandStringList(d.Get("servers").([]interface{}))
+	}
+	if d.HasChange("ldap_port") {
+		m["ldap_port"] = d.Get("ldap_port").(int)
+	}
+	if d.HasChange("use_ldaps") {
+		m["use_ldaps"] = d.Get("use_ldaps").(bool)
+	}
+	if d.HasChange("bind_username") {
+		m["bind_username"] = d.Get("bind_username").(string)
+	}
+	if d.HasChange("bind_password") {
+		m["bind_password"] = d.Get("bind_password").(string)
+	}
+	if d.HasChange("base_dn") || d.HasChange("schema") {
+		m["schema_definition"] = expandLdapSchema(d)
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateLdapConfig(m, config_id)
+		if err != nil {
+			return err
+		}
+		if err := client.TestLdapConfig(config_id); err != nil {
+			return err
+		}
+	}
+	d.Partial(false)
+
+	return resourceIboxLdapConfigRead(d, meta)
+}
+
+func resourceIboxLdapConfigDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	config_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteLdapConfig(config_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
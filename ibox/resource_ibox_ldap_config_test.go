@@ -0,0 +1,77 @@
+package ibox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testLdapConfigResult = `{
+	"id": 1,
+	"name": "corp",
+	"repository_type": "ActiveDirectory",
+	"domain_name": "corp.example.com",
+	"ldap_port": 389,
+	"use_ldaps": false,
+	"bind_username": "svc-ibox",
+	"schema_definition": {"user_search_base_dn": "dc=corp,dc=example,dc=com"}
+}`
+
+func testApplyLdapConfig(t *testing.T, results map[string]string) (*terraform.InstanceState, *fakeApi, error) {
+	api, client := newFakeApi(t, results)
+	meta := &ClientPool{Default: client}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":          "corp",
+		"domain_name":   "corp.example.com",
+		"base_dn":       "dc=corp,dc=example,dc=com",
+		"bind_username": "svc-ibox",
+		"bind_password": "new-password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := resourceIboxLdapConfig()
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":              "1",
+			"name":            "corp",
+			"repository_type": "ActiveDirectory",
+			"domain_name":     "corp.example.com",
+			"ldap_port":       "389",
+			"use_ldaps":       "false",
+			"base_dn":         "dc=corp,dc=example,dc=com",
+			"bind_username":   "svc-ibox",
+			"bind_password":   hashSecret("old-password"),
+			"servers.#":       "0",
+			"schema.#":        "0",
+		},
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newState, err := r.Apply(state, diff, meta)
+	return newState, api, err
+}
+
+func TestResourceIboxLdapConfigUpdateFailedTestKeepsState(t *testing.T) {
+	// Without a result for POST /config/ldap/1/test the connection test fails
+	state, api, err := testApplyLdapConfig(t, map[string]string{
+		"GET /config/ldap/1": testLdapConfigResult,
+		"PUT /config/ldap/1": testLdapConfigResult,
+	})
+	defer api.Close()
+
+	if err == nil {
+		t.Fatal("expected the failed connection test to fail the update")
+	}
+	if len(api.requestsTo("PUT", "/config/ldap/1")) != 1 {
+		t.Fatal("expected the config to be updated before the test")
+	}
+	if got := state.Attributes["bind_password"]; got != hashSecret("old-password") {
+		t.Errorf("the untested bind_password was kept in the state: %v", got)
+	}
+}
@@ -0,0 +1,214 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxLdapRoleMapping() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxLdapRoleMappingCreate,
+		Read:   resourceIboxLdapRoleMappingRead,
+		Update: resourceIboxLdapRoleMappingUpdate,
+		Delete: resourceIboxLdapRoleMappingDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		CustomizeDiff: resourceIboxLdapRoleMappingCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"ldap_config_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"group_name": {
+				Description: "Name of the directory group e.g. storage-admins",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group_dn": {
+				Description: "Distinguished name of the directory group, needed when the group name is not unique",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"role": {
+				Description:  "Role of the group members ADMIN, POOL_ADMIN, READ_ONLY or TECHNICIAN",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringInList(userRoles, false),
+			},
+			"pools": {
+				Description: "Ids of the pools managed by the group members, only for the POOL_ADMIN role. The pools must not set owners in ibox_pool",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceIboxLdapRoleMappingCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	role := d.Get("role").(string)
+	if role != "" && role != "POOL_ADMIN" && d.Get("pools").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("[ERROR] pools can only be set for the POOL_ADMIN role, the role is: %v", role)
+	}
+	return nil
+}
+
+func resourceIboxLdapRoleMappingCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newGroup := Ldap_group{
+		Name:            d.Get("group_name").(string),
+		Type:            "Ldap",
+		Role:            d.Get("role").(string),
+		Ldap_group_name: d.Get("group_name").(string),
+		Ldap_group_dn:   d.Get("group_dn").(string),
+		Ldap_config_id:  d.Get("ldap_config_id").(int),
+	}
+
+	group, err := client.CreateLdapGroup(newGroup)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(group.Id))
+
+	for _, pool_id := range d.Get("pools").(*schema.Set).List() {
+		if err := client.AddPoolOwner(pool_id.(int), group.Id); err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxLdapRoleMappingRead(d, meta)
+}
+
+func resourceIboxLdapRoleMappingRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	group, err := client.ReadLdapGroup(group_id)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		log.Printf("[WARN] Probably the ldap role mapping was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	pool_ids, err := client.ListUserPools(group_id)
+	if err != nil {
+		return err
+	}
+	pools := make([]interface{}, 0, len(pool_ids))
+	for _, pool_id := range pool_ids {
+		pools = append(pools, pool_id)
+	}
+
+	d.Set("ldap_config_id", group.Ldap_config_id)
+	d.Set("group_name", group.Ldap_group_name)
+	d.Set("group_dn", group.Ldap_group_dn)
+	d.Set("role", group.Role)
+	if err := d.Set("pools", pools); err != nil {
+		return fmt.Errorf("[ERROR] Error setting pools: %#v", err)
+	}
+
+	return nil
+}
+
+func resourceIboxLdapRoleMappingUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("group_dn") {
+		m["ldap_group_dn"] = d.Get("group_dn").(string)
+	}
+	if d.HasChange("role") {
+		m["role"] = d.Get("role").(string)
+	}
+
+	// Pools are released before a role change, a POOL_ADMIN must not lose its role while it still owns pools
+	if d.HasChange("pools") {
+		oldv, newv := d.GetChange("pools")
+		oldSet := oldv.(*schema.Set)
+		newSet := newv.(*schema.Set)
+
+		for _, pool_id := range oldSet.Difference(newSet).List() {
+			if err := client.RemovePoolOwner(pool_id.(int), group_id); err != nil {
+				return err
+			}
+		}
+		if len(m) > 0 {
+			if _, err := client.UpdateLdapGroup(m, group_id); err != nil {
+				return err
+			}
+			m = make(map[string]interface{})
+		}
+		for _, pool_id := range newSet.Difference(oldSet).List() {
+			if err := client.AddPoolOwner(pool_id.(int), group_id); err != nil {
+				return err
+			}
+		}
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateLdapGroup(m, group_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxLdapRoleMappingRead(d, meta)
+}
+
+func resourceIboxLdapRoleMappingDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteLdapGroup(group_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -14,6 +14,7 @@ func resourceIboxLun() *schema.Resource {
 		Delete: resourceIboxLunUnmap,
 
 		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
 			"volume_id": {
 				Type:     schema.TypeInt,
 				Required: true,
@@ -31,9 +32,21 @@ func resourceIboxLun() *schema.Resource {
 				ConflictsWith: []string{"host_id"},
 			},
 			"lun": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
+				Description: "LUN id, for NVMe hosts it is the namespace id of the volume",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"namespace_id": {
+				Description: "NVMe namespace id of the volume when the host or cluster uses NVMe over Fabrics, otherwise 0. The array has no separate namespace id field, the LUN id is presented as the namespace id",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"nvme": {
+				Description: "Whether the host or cluster had NVMe ports when the volume was mapped",
+				Type:        schema.TypeBool,
+				Computed:    true,
 			},
 			"clustered": {
 				Type:     schema.TypeBool,
@@ -48,7 +61,10 @@ func resourceIboxLun() *schema.Resource {
 }
 
 func resourceIboxLunMap(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newLun := Lun{
 		Volume_id: d.Get("volume_id").(int),
@@ -73,12 +89,60 @@ func resourceIboxLunMap(d *schema.ResourceData, meta interface{}) error {
 	d.Set("host_id", lun.Host_id)
 	d.Set("host_cluster_id", lun.Host_cluster_id)
 	d.Set("clustered", lun.Clustered)
+	d.Set("lun", lun.Lun)
+
+	setNamespaceId(d, client, lun)
 
 	return nil
 }
 
+// setNamespaceId stores the NVMe namespace id of the mapping. Whether the mapping is NVMe is looked
+// up once and cached in the state, a failed host lookup leaves it unknown so the next read retries it
+// instead of failing here, which would taint the mapping and remap the volume on the next apply.
+func setNamespaceId(d *schema.ResourceData, client *Client, lun *Lun) {
+	nvme, ok := d.GetOkExists("nvme")
+	if !ok {
+		detected, err := lunIsNvme(client, lun)
+		if err != nil {
+			log.Printf("[WARN] Cannot tell whether LUN id: %v is mapped over NVMe, namespace_id is not refreshed: %v", lun.Id, err)
+			return
+		}
+		d.Set("nvme", detected)
+		nvme = detected
+	}
+	if nvme.(bool) {
+		d.Set("namespace_id", lun.Lun)
+	} else {
+		d.Set("namespace_id", 0)
+	}
+}
+
+// lunIsNvme reports whether the LUN is mapped to an NVMe host or to a cluster with NVMe hosts
+func lunIsNvme(client *Client, lun *Lun) (bool, error) {
+	if lun.Host_cluster_id != 0 {
+		host_cluster, err := client.ReadHostCluster(lun.Host_cluster_id)
+		if err != nil || host_cluster == nil {
+			return false, err
+		}
+		for _, host := range host_cluster.Hosts {
+			if hostIsNvme(&host) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	host, err := client.ReadHost(lun.Host_id)
+	if err != nil || host == nil {
+		return false, err
+	}
+	return hostIsNvme(host), nil
+}
+
 func resourceIboxLunQuery(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newLun := Lun{
 		Volume_id:       d.Get("volume_id").(int),
@@ -98,12 +162,25 @@ func resourceIboxLunQuery(d *schema.ResourceData, meta interface{}) error {
 	if lun == nil {
 		log.Printf("[WARN] Probably the LUN was deleted out of band, removing it from state")
 		d.SetId("")
+		return nil
 	}
+	if lun.Host_id == 0 && lun.Host_cluster_id == 0 {
+		lun.Host_id = newLun.Host_id
+		lun.Host_cluster_id = newLun.Host_cluster_id
+	}
+	if lun.Lun == 0 {
+		lun.Lun = newLun.Lun
+	}
+	setNamespaceId(d, client, lun)
+
 	return nil
 }
 
 func resourceIboxLunUnmap(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newLun := Lun{
 		Volume_id:       d.Get("volume_id").(int),
@@ -116,7 +193,7 @@ func resourceIboxLunUnmap(d *schema.ResourceData, meta interface{}) error {
 
 	// host_id := d.Get("host_id").(int)
 	// volume_id := d.Get("volume_id").(int)
-	err := client.LunUnmap(newLun)
+	err = client.LunUnmap(newLun)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,75 @@
+package ibox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testLunState(nvme string) *terraform.InstanceState {
+	state := &terraform.InstanceState{
+		ID: "7",
+		Attributes: map[string]string{
+			"id":           "7",
+			"volume_id":    "3",
+			"host_id":      "1",
+			"lun":          "5",
+			"namespace_id": "0",
+			"clustered":    "false",
+		},
+	}
+	if nvme != "" {
+		state.Attributes["nvme"] = nvme
+	}
+	return state
+}
+
+func TestResourceIboxLunReadNamespaceId(t *testing.T) {
+	cases := []struct {
+		name         string
+		nvme         string
+		hostResult   string
+		lookups      int
+		namespace_id string
+		cached       string
+	}{
+		{"cached nvme", "true", "", 0, "5", "true"},
+		{"cached scsi", "false", "", 0, "0", "false"},
+		{"detected nvme", "", `{"id": 1, "ports": [{"type": "NVME", "address": "nqn.2014-08.com.example:host01"}]}`, 1, "5", "true"},
+		{"detected scsi", "", `{"id": 1, "ports": [{"type": "FC", "address": "21100024ff913bff"}]}`, 1, "0", "false"},
+		{"failed lookup stays unknown", "", "", 1, "0", ""},
+	}
+	for _, c := range cases {
+		results := map[string]string{
+			"GET /hosts/1/luns": `[{"id": 7, "volume_id": 3, "host_id": 1, "lun": 5}]`,
+		}
+		if c.hostResult != "" {
+			results["GET /hosts/1"] = c.hostResult
+		}
+		api, client := newFakeApi(t, results)
+		api.resultFunc = func(r fakeRequest) (string, bool) {
+			// a failing host lookup instead of a missing host
+			if r.Path == "/hosts/1" && c.hostResult == "" {
+				return `{"id": 1, "ports": [`, true
+			}
+			return "", false
+		}
+
+		state, err := resourceIboxLun().Refresh(testLunState(c.nvme), &ClientPool{Default: client})
+		lookups := len(api.requestsTo("GET", "/hosts/1"))
+		api.Close()
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.name, err)
+			continue
+		}
+		if lookups != c.lookups {
+			t.Errorf("%v: expected %v host lookups, got %v", c.name, c.lookups, lookups)
+		}
+		if got := state.Attributes["namespace_id"]; got != c.namespace_id {
+			t.Errorf("%v: namespace_id = %v, expected %v", c.name, got, c.namespace_id)
+		}
+		if got := state.Attributes["nvme"]; got != c.cached {
+			t.Errorf("%v: nvme = %q, expected %q", c.name, got, c.cached)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxNetworkInterface() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxNetworkInterfaceCreate,
+		Read:   resourceIboxNetworkInterfaceRead,
+		Update: resourceIboxNetworkInterfaceUpdate,
+		Delete: resourceIboxNetworkInterfaceDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"node": {
+				Description: "Node id the interface belongs to",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"type": {
+				Description: "Interface type PORT_GROUP or VLAN",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validateStringInList([]string{
+					"PORT_GROUP",
+					"VLAN",
+				}, false),
+			},
+			"ports": {
+				Description: "Ethernet ports of the port group e.g. eth-data1",
+				Type:        schema.TypeList,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"vlan_id": {
+				Description:  "VLAN id of VLAN interfaces",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIntegerInRange(1, 4094),
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func expandStringList(list []interface{}) []string {
+	strs := make([]string, 0, len(list))
+	for _, v := range list {
+		strs = append(strs, v.(string))
+	}
+	return strs
+}
+
+func resourceIboxNetworkInterfaceCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newInterface := Network_interface{
+		Node_id: d.Get("node").(int),
+		Type:    d.Get("type").(string),
+		Ports:   expandStringList(d.Get("ports").([]interface{})),
+		Vlan_id: d.Get("vlan_id").(int),
+	}
+
+	if newInterface.Type == "VLAN" && newInterface.Vlan_id == 0 {
+		return fmt.Errorf("[ERROR] vlan_id must be set for VLAN network interfaces")
+	}
+
+	networkInterface, err := client.CreateNetworkInterface(newInterface)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(networkInterface.Id))
+
+	return resourceIboxNetworkInterfaceRead(d, meta)
+}
+
+func resourceIboxNetworkInterfaceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	interface_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	networkInterface, err := client.ReadNetworkInterface(interface_id)
+	if err != nil {
+		return err
+	}
+	if networkInterface == nil {
+		log.Printf("[WARN] Probably the network interface was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", networkInterface.Node_id)
+	d.Set("type", networkInterface.Type)
+	d.Set("vlan_id", networkInterface.Vlan_id)
+	d.Set("state", networkInterface.State)
+	if err := d.Set("ports", networkInterface.Ports); err != nil {
+		return fmt.Errorf("[ERROR] Error setting ports: %#v", err)
+	}
+
+	return nil
+}
+
+func resourceIboxNetworkInterfaceUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	interface_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if d.HasChange("ports") {
+		var m map[string]interface{}
+		m = make(map[string]interface{})
+		m["ports"] = expandStringList(d.Get("ports").([]interface{}))
+
+		_, err := client.UpdateNetworkInterface(m, interface_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxNetworkInterfaceRead(d, meta)
+}
+
+func resourceIboxNetworkInterfaceDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	interface_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteNetworkInterface(interface_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
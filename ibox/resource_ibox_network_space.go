@@ -0,0 +1,262 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxNetworkSpace() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxNetworkSpaceCreate,
+		Read:   resourceIboxNetworkSpaceRead,
+		Update: resourceIboxNetworkSpaceUpdate,
+		Delete: resourceIboxNetworkSpaceDelete,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"service": {
+				Description: "Service of the network space ISCSI_SERVICE, NAS_SERVICE or RMR_SERVICE",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validateStringInList([]string{
+					"ISCSI_SERVICE",
+					"NAS_SERVICE",
+					"RMR_SERVICE",
+				}, false),
+			},
+			"interfaces": {
+				Description: "Network interface ids the network space is configured on",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"ips": {
+				Description: "IP addresses of the network space",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIPAddress,
+				},
+			},
+			"network": {
+				Description:  "Network address e.g. 10.0.0.0",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPAddress,
+			},
+			"netmask": {
+				Description:  "Netmask prefix length e.g. 24",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntegerInRange(1, 32),
+			},
+			"default_gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateIPAddress,
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntegerInRange(1280, 9000),
+			},
+			"rate_limit": {
+				Description: "Rate limit of the network space in Mbps, 0 means unlimited",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func expandNetworkConfig(d *schema.ResourceData) *Network_config {
+	return &Network_config{
+		Network:         d.Get("network").(string),
+		Netmask:         d.Get("netmask").(int),
+		Default_gateway: d.Get("default_gateway").(string),
+	}
+}
+
+func expandIntSet(set *schema.Set) []int {
+	list := make([]int, 0, set.Len())
+	for _, v := range set.List() {
+		list = append(list, v.(int))
+	}
+	return list
+}
+
+func resourceIboxNetworkSpaceCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newSpace := Network_space{
+		Name:           d.Get("name").(string),
+		Service:        d.Get("service").(string),
+		Interfaces:     expandIntSet(d.Get("interfaces").(*schema.Set)),
+		Network_config: expandNetworkConfig(d),
+		Mtu:            d.Get("mtu").(int),
+		Rate_limit:     d.Get("rate_limit").(int),
+	}
+
+	space, err := client.CreateNetworkSpace(newSpace)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(space.Id))
+
+	for _, ip := range d.Get("ips").(*schema.Set).List() {
+		if err := client.AddNetworkSpaceIp(space.Id, ip.(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxNetworkSpaceRead(d, meta)
+}
+
+func resourceIboxNetworkSpaceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	space_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	space, err := client.ReadNetworkSpace(space_id)
+	if err != nil {
+		return err
+	}
+	if space == nil {
+		log.Printf("[WARN] Probably the network space was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	interfaces := make([]interface{}, 0, len(space.Interfaces))
+	for _, interface_id := range space.Interfaces {
+		interfaces = append(interfaces, interface_id)
+	}
+	ips := make([]interface{}, 0, len(space.Ips))
+	for _, ip := range space.Ips {
+		ips = append(ips, ip.Ip_address)
+	}
+
+	d.Set("name", space.Name)
+	d.Set("service", space.Service)
+	d.Set("mtu", space.Mtu)
+	d.Set("rate_limit", space.Rate_limit)
+	if space.Network_config != nil {
+		d.Set("network", space.Network_config.Network)
+		d.Set("netmask", space.Network_config.Netmask)
+		d.Set("default_gateway", space.Network_config.Default_gateway)
+	}
+	if err := d.Set("interfaces", interfaces); err != nil {
+		return fmt.Errorf("[ERROR] Error setting interfaces: %#v", err)
+	}
+	if err := d.Set("ips", ips); err != nil {
+		return fmt.Errorf("[ERROR] Error setting ips: %#v", err)
+	}
+
+	return nil
+}
+
+func resourceIboxNetworkSpaceUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	space_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("mtu") {
+		m["mtu"] = d.Get("mtu").(int)
+	}
+	if d.HasChange("rate_limit") {
+		m["rate_limit"] = d.Get("rate_limit").(int)
+	}
+	if d.HasChange("interfaces") {
+		m["interfaces"] = expandIntSet(d.Get("interfaces").(*schema.Set))
+	}
+	if d.HasChange("network") || d.HasChange("netmask") || d.HasChange("default_gateway") {
+		m["network_config"] = expandNetworkConfig(d)
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateNetworkSpace(m, space_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("ips") {
+		oldv, newv := d.GetChange("ips")
+		oldSet := oldv.(*schema.Set)
+		newSet := newv.(*schema.Set)
+
+		for _, ip := range newSet.Difference(oldSet).List() {
+			if err := client.AddNetworkSpaceIp(space_id, ip.(string)); err != nil {
+				return err
+			}
+		}
+		for _, ip := range oldSet.Difference(newSet).List() {
+			if err := client.RemoveNetworkSpaceIp(space_id, ip.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceIboxNetworkSpaceRead(d, meta)
+}
+
+func resourceIboxNetworkSpaceDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	space_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	// The array refuses to delete a network space which still has ip addresses
+	for _, ip := range d.Get("ips").(*schema.Set).List() {
+		if err := client.RemoveNetworkSpaceIp(space_id, ip.(string)); err != nil {
+			return err
+		}
+	}
+
+	err = client.DeleteNetworkSpace(space_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
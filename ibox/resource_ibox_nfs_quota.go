@@ -0,0 +1,207 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+// quotaOwnerKey returns the attribute holding the owner of a user or group quota
+func quotaOwnerKey(kind string) string {
+	if kind == "group" {
+		return "gid"
+	}
+	return "uid"
+}
+
+// resourceIboxNfsQuota returns ibox_nfs_user_quota or ibox_nfs_group_quota, kind is user or group
+func resourceIboxNfsQuota(kind string) *schema.Resource {
+	return &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceIboxNfsQuotaCreate(kind, d, meta)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceIboxNfsQuotaRead(kind, d, meta)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceIboxNfsQuotaUpdate(kind, d, meta)
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceIboxNfsQuotaDelete(kind, d, meta)
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: importFilesystemChild("quota_id"),
+		},
+
+		CustomizeDiff: customizeQuotaDiff,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"filesystem_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			quotaOwnerKey(kind): {
+				Description: "Numeric " + kind + " id the quota applies to",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"soft_capacity": {
+				Description: "Soft capacity limit in bytes, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"hard_capacity": {
+				Description: "Hard capacity limit in bytes, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"soft_inodes": {
+				Description: "Soft limit of the number of files and directories, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"hard_inodes": {
+				Description: "Hard limit of the number of files and directories, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"used_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"used_inodes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// quotaOwner returns the uid or gid of a quota
+func quotaOwner(kind string, quota *Nfs_quota) int {
+	if kind == "group" && quota.Gid != nil {
+		return *quota.Gid
+	}
+	if kind == "user" && quota.Uid != nil {
+		return *quota.Uid
+	}
+	return 0
+}
+
+func resourceIboxNfsQuotaCreate(kind string, d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	filesystem_id := d.Get("filesystem_id").(int)
+	owner := d.Get(quotaOwnerKey(kind)).(int)
+	newQuota := Nfs_quota{
+		Soft_capacity: d.Get("soft_capacity").(int),
+		Hard_capacity: d.Get("hard_capacity").(int),
+		Soft_inodes:   d.Get("soft_inodes").(int),
+		Hard_inodes:   d.Get("hard_inodes").(int),
+	}
+	if kind == "group" {
+		newQuota.Gid = &owner
+	} else {
+		newQuota.Uid = &owner
+	}
+
+	quota, err := client.CreateNfsQuota(kind, filesystem_id, newQuota)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(quota.Id))
+
+	return resourceIboxNfsQuotaRead(kind, d, meta)
+}
+
+func resourceIboxNfsQuotaRead(kind string, d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	quota_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	quota, err := client.ReadNfsQuota(kind, d.Get("filesystem_id").(int), quota_id)
+	if err != nil {
+		return err
+	}
+	if quota == nil {
+		log.Printf("[WARN] Probably the nfs %v quota was deleted out of band, removing it from state", kind)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(quotaOwnerKey(kind), quotaOwner(kind, quota))
+	d.Set("soft_capacity", quota.Soft_capacity)
+	d.Set("hard_capacity", quota.Hard_capacity)
+	d.Set("soft_inodes", quota.Soft_inodes)
+	d.Set("hard_inodes", quota.Hard_inodes)
+	d.Set("used_capacity", quota.Used_capacity)
+	d.Set("used_inodes", quota.Used_inodes)
+
+	return nil
+}
+
+func resourceIboxNfsQuotaUpdate(kind string, d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	quota_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	for _, k := range []string{"soft_capacity", "hard_capacity", "soft_inodes", "hard_inodes"} {
+		if d.HasChange(k) {
+			m[k] = quotaLimit(d.Get(k).(int))
+		}
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateNfsQuota(m, kind, d.Get("filesystem_id").(int), quota_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxNfsQuotaRead(kind, d, meta)
+}
+
+func resourceIboxNfsQuotaDelete(kind string, d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	quota_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteNfsQuota(kind, d.Get("filesystem_id").(int), quota_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
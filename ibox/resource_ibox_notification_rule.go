@@ -0,0 +1,232 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxNotificationRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxNotificationRuleCreate,
+		Read:   resourceIboxNotificationRuleRead,
+		Update: resourceIboxNotificationRuleUpdate,
+		Delete: resourceIboxNotificationRuleDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"event_levels": {
+				Description: "Event levels which trigger the rule INFO, WARNING, ERROR or CRITICAL",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validateStringInList([]string{
+						"INFO",
+						"WARNING",
+						"ERROR",
+						"CRITICAL",
+					}, false),
+				},
+			},
+			"event_codes": {
+				Description: "Only these event codes trigger the rule e.g. VOLUME_CREATED, all codes when empty",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"visibility": {
+				Description: "Event visibility CUSTOMER or INFINIDAT",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validateStringInList([]string{
+						"CUSTOMER",
+						"INFINIDAT",
+					}, false),
+				},
+			},
+			"target": {
+				Description: "Notification targets the matching events are sent to",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"target_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"recipients": {
+							Description: "Mail addresses, only for SMTP targets",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func expandStringSet(set *schema.Set) []string {
+	return expandStringList(set.List())
+}
+
+func expandNotificationRuleTargets(set *schema.Set) []Notification_rule_target {
+	targets := make([]Notification_rule_target, 0, set.Len())
+	for _, v := range set.List() {
+		m := v.(map[string]interface{})
+		targets = append(targets, Notification_rule_target{
+			Target_id:  m["target_id"].(int),
+			Recipients: expandStringList(m["recipients"].([]interface{})),
+		})
+	}
+	return targets
+}
+
+func resourceIboxNotificationRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newRule := Notification_rule{
+		Name:              d.Get("name").(string),
+		Event_level:       expandStringSet(d.Get("event_levels").(*schema.Set)),
+		Include_events:    expandStringSet(d.Get("event_codes").(*schema.Set)),
+		Event_visibility:  expandStringSet(d.Get("visibility").(*schema.Set)),
+		Target_parameters: expandNotificationRuleTargets(d.Get("target").(*schema.Set)),
+	}
+
+	rule, err := client.CreateNotificationRule(newRule)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(rule.Id))
+
+	return resourceIboxNotificationRuleRead(d, meta)
+}
+
+func resourceIboxNotificationRuleRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	rule_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	rule, err := client.ReadNotificationRule(rule_id)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		log.Printf("[WARN] Probably the notification rule was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	targets := make([]map[string]interface{}, 0, len(rule.Target_parameters))
+	for _, target := range rule.Target_parameters {
+		targets = append(targets, map[string]interface{}{
+			"target_id":  target.Target_id,
+			"recipients": target.Recipients,
+		})
+	}
+
+	d.Set("name", rule.Name)
+	if err := d.Set("event_levels", rule.Event_level); err != nil {
+		return fmt.Errorf("[ERROR] Error setting event_levels: %#v", err)
+	}
+	if err := d.Set("event_codes", rule.Include_events); err != nil {
+		return fmt.Errorf("[ERROR] Error setting event_codes: %#v", err)
+	}
+	if err := d.Set("visibility", rule.Event_visibility); err != nil {
+		return fmt.Errorf("[ERROR] Error setting visibility: %#v", err)
+	}
+	if err := d.Set("target", targets); err != nil {
+		return fmt.Errorf("[ERROR] Error setting target: %#v", err)
+	}
+
+	return nil
+}
+
+func resourceIboxNotificationRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	rule_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("event_levels") {
+		m["event_level"] = expandStringSet(d.Get("event_levels").(*schema.Set))
+	}
+	if d.HasChange("event_codes") {
+		m["include_events"] = expandStringSet(d.Get("event_codes").(*schema.Set))
+	}
+	if d.HasChange("visibility") {
+		m["event_visibility"] = expandStringSet(d.Get("visibility").(*schema.Set))
+	}
+	if d.HasChange("target") {
+		m["target_parameters"] = expandNotificationRuleTargets(d.Get("target").(*schema.Set))
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateNotificationRule(m, rule_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxNotificationRuleRead(d, meta)
+}
+
+func resourceIboxNotificationRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	rule_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteNotificationRule(rule_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,251 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxNotificationTarget() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxNotificationTargetCreate,
+		Read:   resourceIboxNotificationTargetRead,
+		Update: resourceIboxNotificationTargetUpdate,
+		Delete: resourceIboxNotificationTargetDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		CustomizeDiff: resourceIboxNotificationTargetCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"protocol": {
+				Description: "Target type SMTP, SNMP or SYSLOG",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validateStringInList([]string{
+					"SMTP",
+					"SNMP",
+					"SYSLOG",
+				}, false),
+			},
+			"host": {
+				Description: "Mail relay, SNMP manager or syslog server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntegerInRange(1, 65535),
+			},
+			"username": {
+				Description: "SMTP user, or SNMPv3 user",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"password": {
+				Description: "SMTP or SNMPv3 password, only its SHA256 hash is kept in the state",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"from_address": {
+				Description: "Sender address of SMTP notifications",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"tls": {
+				Description: "Use TLS to connect to the SMTP relay",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"version": {
+				Description: "SNMP version SNMPv2c or SNMPv3",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateFunc: validateStringInList([]string{
+					"SNMPv2c",
+					"SNMPv3",
+				}, false),
+			},
+			"community": {
+				Description: "SNMPv2c community, only its SHA256 hash is kept in the state",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"transport": {
+				Description: "Syslog transport UDP or TCP",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateFunc: validateStringInList([]string{
+					"UDP",
+					"TCP",
+				}, false),
+			},
+			"facility": {
+				Description: "Syslog facility e.g. LOCAL0",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Settings which only apply to one protocol
+var notificationTargetSettings = map[string][]string{
+	"SMTP":   {"from_address", "tls"},
+	"SNMP":   {"version", "community"},
+	"SYSLOG": {"transport", "facility"},
+}
+
+func resourceIboxNotificationTargetCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	protocol := d.Get("protocol").(string)
+	for other, keys := range notificationTargetSettings {
+		if other == protocol {
+			continue
+		}
+		for _, k := range keys {
+			if v, ok := d.GetOk(k); ok && v != false {
+				return fmt.Errorf("[ERROR] %v is only valid for %v targets, the protocol is: %v", k, other, protocol)
+			}
+		}
+	}
+	if protocol == "SMTP" && d.Get("from_address").(string) == "" {
+		return fmt.Errorf("[ERROR] from_address must be set for SMTP targets")
+	}
+	if protocol == "SNMP" && d.Get("version").(string) == "SNMPv2c" && d.Get("community").(string) == "" {
+		return fmt.Errorf("[ERROR] community must be set for SNMPv2c targets")
+	}
+	return nil
+}
+
+func resourceIboxNotificationTargetCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newTarget := Notification_target{
+		Name:         d.Get("name").(string),
+		Protocol:     d.Get("protocol").(string),
+		Host:         d.Get("host").(string),
+		Port:         d.Get("port").(int),
+		Username:     d.Get("username").(string),
+		Password:     d.Get("password").(string),
+		From_address: d.Get("from_address").(string),
+		Tls:          d.Get("tls").(bool),
+		Version:      d.Get("version").(string),
+		Community:    d.Get("community").(string),
+		Transport:    d.Get("transport").(string),
+		Facility:     d.Get("facility").(string),
+	}
+
+	target, err := client.CreateNotificationTarget(newTarget)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(target.Id))
+
+	return resourceIboxNotificationTargetRead(d, meta)
+}
+
+func resourceIboxNotificationTargetRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	target_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	target, err := client.ReadNotificationTarget(target_id)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		log.Printf("[WARN] Probably the notification target was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	return setReadFields(d, map[string]interface{}{
+		"name":         target.Name,
+		"protocol":     target.Protocol,
+		"host":         target.Host,
+		"port":         target.Port,
+		"username":     target.Username,
+		"from_address": target.From_address,
+		"tls":          target.Tls,
+		"version":      target.Version,
+		"transport":    target.Transport,
+		"facility":     target.Facility,
+	})
+}
+
+func resourceIboxNotificationTargetUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	target_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	for _, k := range []string{"name", "host", "port", "username", "password", "from_address", "tls", "version", "community", "transport", "facility"} {
+		if d.HasChange(k) {
+			m[k] = d.Get(k)
+		}
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateNotificationTarget(m, target_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxNotificationTargetRead(d, meta)
+}
+
+func resourceIboxNotificationTargetDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	target_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteNotificationTarget(target_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
+	"math"
 	"strconv"
 )
 
@@ -14,7 +15,10 @@ func resourceIboxPool() *schema.Resource {
 		Update: resourceIboxPoolUpdate,
 		Delete: resourceIboxPoolDelete,
 
+		CustomizeDiff: resourceIboxPoolCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
 			"name": {
 				Description: "Pool name",
 				Type:        schema.TypeString,
@@ -56,6 +60,74 @@ func resourceIboxPool() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 			},
+			"owners": {
+				Description: "Ids of the POOL_ADMIN users managing the pool, when set it replaces all owners of the pool",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"allocated_physical_capacity": {
+				Description: "Physical capacity used by the pool in bytes",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"reserved_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"free_physical_capacity": {
+				Description: "physical_capacity minus allocated_physical_capacity in bytes",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"free_virtual_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"physical_capacity_used_percent": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"virtual_capacity_used_percent": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"physical_capacity_warning_reached": {
+				Description: "The used physical capacity reached physical_capacity_warning",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"physical_capacity_critical_reached": {
+				Description: "The used physical capacity reached physical_capacity_critical",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"volumes_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"snapshots_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"filesystems_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"entities_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": {
+				Description: "Pool state e.g. NORMAL, LIMITED or LOCKED",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"metadata":     metadataSchema(),
+			"metadata_all": metadataAllSchema(),
 			"id": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -65,7 +137,10 @@ func resourceIboxPool() *schema.Resource {
 }
 
 func resourceIboxPoolCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newPool := Pool{
 		Name:                       d.Get("name").(string),
@@ -77,6 +152,10 @@ func resourceIboxPoolCreate(d *schema.ResourceData, meta interface{}) error {
 		Virtual_capacity:           d.Get("virtual_capacity").(int),
 		Physical_capacity:          d.Get("physical_capacity").(int),
 	}
+	// Without owners in the configuration the owners are left to ibox_ldap_role_mapping and the GUI
+	if v, ok := d.GetOk("owners"); ok {
+		newPool.Owners = expandIntSet(v.(*schema.Set))
+	}
 
 	// virtual_capacity_size := d.Get("virtual_capacity").(int)
 	// if virtual_capacity_size < pool_min_size {
@@ -105,12 +184,18 @@ func resourceIboxPoolCreate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	} else {
 		d.SetId(strconv.Itoa(pool.Id))
-		return nil
+		if err := createMetadata(d, client, pool.Id); err != nil {
+			return err
+		}
+		return resourceIboxPoolRead(d, meta)
 	}
 }
 
 func resourceIboxPoolRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	pool, err := client.ReadPool(d.Id())
 	if err != nil {
@@ -122,14 +207,26 @@ func resourceIboxPoolRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 	d.Set("name", pool.Name)
+	setPoolCapacity(d, pool)
 
-	return nil
+	owners := make([]interface{}, 0, len(pool.Owners))
+	for _, user_id := range pool.Owners {
+		owners = append(owners, user_id)
+	}
+	if err := d.Set("owners", owners); err != nil {
+		return fmt.Errorf("[ERROR] Error setting owners: %#v", err)
+	}
+
+	return readMetadata(d, client, pool.Id)
 }
 
 func resourceIboxPoolDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
-	err := client.DeletePool(d.Id())
+	err = client.DeletePool(d.Id())
 	if err != nil {
 		return err
 	}
@@ -137,7 +234,10 @@ func resourceIboxPoolDelete(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceIboxPoolUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	pool_id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -151,6 +251,17 @@ func resourceIboxPoolUpdate(d *schema.ResourceData, meta interface{}) error {
 		var m map[string]interface{}
 		m = make(map[string]interface{})
 
+		if k == "metadata_all" {
+			continue
+		}
+		if k == "metadata" {
+			if err := updateMetadata(d, client, pool_id); err != nil {
+				return err
+			}
+			d.SetPartial(k)
+			continue
+		}
+
 		if d.HasChange(k) {
 			old_value, new_value := d.GetChange(k)
 			log.Printf("[DEBUG] %v has changed from: %v to: %v", k, old_value, new_value)
@@ -160,7 +271,11 @@ func resourceIboxPoolUpdate(d *schema.ResourceData, meta interface{}) error {
 			// 		return fmt.Errorf("[ERROR] updating key: %v, error: %v", k, err)
 			// 	}
 			// }
-			m[k] = d.Get(k)
+			if k == "owners" {
+				m[k] = expandIntSet(d.Get(k).(*schema.Set))
+			} else {
+				m[k] = d.Get(k)
+			}
 
 			_, err := client.UpdatePool(m, pool_id)
 			if err != nil {
@@ -170,5 +285,39 @@ func resourceIboxPoolUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 	d.Partial(false)
-	return nil
+	return resourceIboxPoolRead(d, meta)
+}
+
+func resourceIboxPoolCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := requireFeatureIf(d, meta, "compression_enabled", "compression"); err != nil {
+		return err
+	}
+	return customizeMetadataDiff(d, meta)
+}
+
+// usedPercent returns the used part of the capacity in percent, rounded to two decimals
+func usedPercent(used int, capacity int) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return math.Floor(float64(used)*10000/float64(capacity)+0.5) / 100
+}
+
+// setPoolCapacity stores the usage of the pool and the capacity derived from it
+func setPoolCapacity(d *schema.ResourceData, pool *Pool) {
+	physicalUsed := usedPercent(pool.Allocated_physical_capacity, pool.Physical_capacity)
+
+	d.Set("allocated_physical_capacity", pool.Allocated_physical_capacity)
+	d.Set("reserved_capacity", pool.Reserved_capacity)
+	d.Set("free_physical_capacity", pool.Physical_capacity-pool.Allocated_physical_capacity)
+	d.Set("free_virtual_capacity", pool.Free_virtual_capacity)
+	d.Set("physical_capacity_used_percent", physicalUsed)
+	d.Set("virtual_capacity_used_percent", usedPercent(pool.Virtual_capacity-pool.Free_virtual_capacity, pool.Virtual_capacity))
+	d.Set("physical_capacity_warning_reached", pool.Physical_capacity_warning > 0 && physicalUsed >= float64(pool.Physical_capacity_warning))
+	d.Set("physical_capacity_critical_reached", pool.Physical_capacity_critical > 0 && physicalUsed >= float64(pool.Physical_capacity_critical))
+	d.Set("volumes_count", pool.Volumes_count)
+	d.Set("snapshots_count", pool.Snapshots_count)
+	d.Set("filesystems_count", pool.Filesystems_count)
+	d.Set("entities_count", pool.Entities_count)
+	d.Set("state", pool.State)
 }
@@ -0,0 +1,95 @@
+package ibox
+
+import (
+	"testing"
+)
+
+func TestUsedPercent(t *testing.T) {
+	cases := []struct {
+		name     string
+		used     int
+		capacity int
+		expected float64
+	}{
+		{"empty", 0, 1000, 0},
+		{"full", 1000, 1000, 100},
+		{"half", 500, 1000, 50},
+		{"rounded down", 1, 3, 33.33},
+		{"rounded up", 2, 3, 66.67},
+		{"half rounds up", 12345, 100000, 12.35},
+		{"over allocated", 1500, 1000, 150},
+		{"zero capacity", 500, 0, 0},
+		{"negative capacity", 500, -1, 0},
+		{"large pool", 1100000000000 / 4, 1100000000000, 25},
+	}
+	for _, c := range cases {
+		if got := usedPercent(c.used, c.capacity); got != c.expected {
+			t.Errorf("%v: usedPercent(%v, %v) = %v, expected %v", c.name, c.used, c.capacity, got, c.expected)
+		}
+	}
+}
+
+func TestSetPoolCapacity(t *testing.T) {
+	cases := []struct {
+		name             string
+		capacity         int
+		allocated        int
+		warning          int
+		critical         int
+		percent          float64
+		warning_reached  bool
+		critical_reached bool
+	}{
+		{"below warning", 10000, 7999, 80, 90, 79.99, false, false},
+		{"rounded up to warning", 100000, 79996, 80, 90, 80, true, false},
+		{"at warning", 10000, 8000, 80, 90, 80, true, false},
+		{"between thresholds", 10000, 8500, 80, 90, 85, true, false},
+		{"at critical", 10000, 9000, 80, 90, 90, true, true},
+		{"above critical", 10000, 9900, 80, 90, 99, true, true},
+		{"thresholds disabled", 10000, 9900, 0, 0, 99, false, false},
+	}
+	for _, c := range cases {
+		capacity := c.capacity
+		pool := &Pool{
+			Physical_capacity:           capacity,
+			Allocated_physical_capacity: c.allocated,
+			Virtual_capacity:            2 * capacity,
+			Free_virtual_capacity:       capacity / 2,
+			Physical_capacity_warning:   c.warning,
+			Physical_capacity_critical:  c.critical,
+		}
+		d := resourceIboxPool().TestResourceData()
+		setPoolCapacity(d, pool)
+
+		if got := d.Get("physical_capacity_used_percent").(float64); got != c.percent {
+			t.Errorf("%v: physical_capacity_used_percent = %v, expected %v", c.name, got, c.percent)
+		}
+		if got := d.Get("virtual_capacity_used_percent").(float64); got != 75 {
+			t.Errorf("%v: virtual_capacity_used_percent = %v, expected 75", c.name, got)
+		}
+		if got := d.Get("free_physical_capacity").(int); got != capacity-c.allocated {
+			t.Errorf("%v: free_physical_capacity = %v, expected %v", c.name, got, capacity-c.allocated)
+		}
+		if got := d.Get("physical_capacity_warning_reached").(bool); got != c.warning_reached {
+			t.Errorf("%v: physical_capacity_warning_reached = %v, expected %v", c.name, got, c.warning_reached)
+		}
+		if got := d.Get("physical_capacity_critical_reached").(bool); got != c.critical_reached {
+			t.Errorf("%v: physical_capacity_critical_reached = %v, expected %v", c.name, got, c.critical_reached)
+		}
+	}
+}
+
+func TestSetPoolCapacityZeroCapacity(t *testing.T) {
+	d := resourceIboxPool().TestResourceData()
+	setPoolCapacity(d, &Pool{Physical_capacity_warning: 80, Physical_capacity_critical: 90})
+
+	if got := d.Get("physical_capacity_used_percent").(float64); got != 0 {
+		t.Errorf("physical_capacity_used_percent = %v, expected 0", got)
+	}
+	if got := d.Get("virtual_capacity_used_percent").(float64); got != 0 {
+		t.Errorf("virtual_capacity_used_percent = %v, expected 0", got)
+	}
+	if d.Get("physical_capacity_warning_reached").(bool) || d.Get("physical_capacity_critical_reached").(bool) {
+		t.Error("expected no threshold to be reached by a pool without capacity")
+	}
+}
@@ -0,0 +1,127 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxSmbGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxSmbGroupCreate,
+		Read:   resourceIboxSmbGroupRead,
+		Update: resourceIboxSmbGroupUpdate,
+		Delete: resourceIboxSmbGroupDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"gid": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceIboxSmbGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newGroup := Smb_group{
+		Name: d.Get("name").(string),
+	}
+
+	group, err := client.CreateSmbGroup(newGroup)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(group.Id))
+
+	return resourceIboxSmbGroupRead(d, meta)
+}
+
+func resourceIboxSmbGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	group, err := client.ReadSmbGroup(group_id)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		log.Printf("[WARN] Probably the smb group was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", group.Name)
+	d.Set("gid", group.Gid)
+
+	return nil
+}
+
+func resourceIboxSmbGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	if d.HasChange("name") {
+		var m map[string]interface{}
+		m = make(map[string]interface{})
+		m["name"] = d.Get("name").(string)
+
+		_, err := client.UpdateSmbGroup(m, group_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxSmbGroupRead(d, meta)
+}
+
+func resourceIboxSmbGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteSmbGroup(group_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
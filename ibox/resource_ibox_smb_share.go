@@ -0,0 +1,223 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxSmbShare() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxSmbShareCreate,
+		Read:   resourceIboxSmbShareRead,
+		Update: resourceIboxSmbShareUpdate,
+		Delete: resourceIboxSmbShareDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Description: "Share name as seen by the SMB clients",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"filesystem_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"inner_path": {
+				Description: "Directory of the filesystem exported by the share",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "/",
+			},
+			"access_based_enumeration": {
+				Description: "Hide the files and folders the user has no access to",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"offline_caching": {
+				Description: "Offline caching mode MANUAL, DOCUMENTS, PROGRAMS or NONE",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "MANUAL",
+				ValidateFunc: validateStringInList([]string{
+					"MANUAL",
+					"DOCUMENTS",
+					"PROGRAMS",
+					"NONE",
+				}, false),
+			},
+			"permissions": {
+				Description: "Access levels of users and groups to the share",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"account": {
+							Description: "User or group name e.g. DOMAIN\\Domain Users",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"access": {
+							Description: "Access level FULLCONTROL, CHANGE, READ or NONE",
+							Type:        schema.TypeString,
+							Required:    true,
+							ValidateFunc: validateStringInList([]string{
+								"FULLCONTROL",
+								"CHANGE",
+								"READ",
+								"NONE",
+							}, false),
+						},
+					},
+				},
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func expandSmbSharePermissions(set *schema.Set) []Smb_share_permission {
+	permissions := make([]Smb_share_permission, 0, set.Len())
+	for _, v := range set.List() {
+		m := v.(map[string]interface{})
+		permissions = append(permissions, Smb_share_permission{
+			Account: m["account"].(string),
+			Access:  m["access"].(string),
+		})
+	}
+	return permissions
+}
+
+func resourceIboxSmbShareCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newShare := Smb_share{
+		Name:                     d.Get("name").(string),
+		Filesystem_id:            d.Get("filesystem_id").(int),
+		Inner_path:               d.Get("inner_path").(string),
+		Access_based_enumeration: d.Get("access_based_enumeration").(bool),
+		Offline_caching:          d.Get("offline_caching").(string),
+		Permissions:              expandSmbSharePermissions(d.Get("permissions").(*schema.Set)),
+	}
+
+	share, err := client.CreateSmbShare(newShare)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(share.Id))
+
+	return resourceIboxSmbShareRead(d, meta)
+}
+
+func resourceIboxSmbShareRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	share_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	share, err := client.ReadSmbShare(share_id)
+	if err != nil {
+		return err
+	}
+	if share == nil {
+		log.Printf("[WARN] Probably the smb share was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	permissions := make([]map[string]interface{}, 0, len(share.Permissions))
+	for _, permission := range share.Permissions {
+		permissions = append(permissions, map[string]interface{}{
+			"account": permission.Account,
+			"access":  permission.Access,
+		})
+	}
+
+	d.Set("name", share.Name)
+	d.Set("filesystem_id", share.Filesystem_id)
+	d.Set("inner_path", share.Inner_path)
+	d.Set("access_based_enumeration", share.Access_based_enumeration)
+	d.Set("offline_caching", share.Offline_caching)
+	if err := d.Set("permissions", permissions); err != nil {
+		return fmt.Errorf("[ERROR] Error setting permissions: %#v", err)
+	}
+
+	return nil
+}
+
+func resourceIboxSmbShareUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	share_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("access_based_enumeration") {
+		m["access_based_enumeration"] = d.Get("access_based_enumeration").(bool)
+	}
+	if d.HasChange("offline_caching") {
+		m["offline_caching"] = d.Get("offline_caching").(string)
+	}
+	// The array replaces the whole permission list of the share
+	if d.HasChange("permissions") {
+		m["permissions"] = expandSmbSharePermissions(d.Get("permissions").(*schema.Set))
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateSmbShare(m, share_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxSmbShareRead(d, meta)
+}
+
+func resourceIboxSmbShareDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	share_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteSmbShare(share_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,169 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxSmbUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxSmbUserCreate,
+		Read:   resourceIboxSmbUserRead,
+		Update: resourceIboxSmbUserUpdate,
+		Delete: resourceIboxSmbUserDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password": {
+				Description: "Password of the local SMB user, only its SHA256 hash is kept in the state",
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"primary_group_id": {
+				Description: "Id of the ibox_smb_group the user belongs to",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			"uid": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceIboxSmbUserCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newUser := Smb_user{
+		Name:             d.Get("name").(string),
+		Password:         d.Get("password").(string),
+		Enabled:          d.Get("enabled").(bool),
+		Primary_group_id: d.Get("primary_group_id").(int),
+	}
+
+	user, err := client.CreateSmbUser(newUser)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(user.Id))
+
+	err = disableAfterCreate(d, func(m map[string]interface{}) error {
+		_, err := client.UpdateSmbUser(m, user.Id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceIboxSmbUserRead(d, meta)
+}
+
+func resourceIboxSmbUserRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	user, err := client.ReadSmbUser(user_id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		log.Printf("[WARN] Probably the smb user was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	return setReadFields(d, map[string]interface{}{
+		"name":             user.Name,
+		"enabled":          user.Enabled,
+		"primary_group_id": user.Primary_group_id,
+		"uid":              user.Uid,
+	})
+}
+
+func resourceIboxSmbUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("password") {
+		m["password"] = d.Get("password").(string)
+	}
+	if d.HasChange("enabled") {
+		m["enabled"] = d.Get("enabled").(bool)
+	}
+	if d.HasChange("primary_group_id") {
+		m["primary_group_id"] = d.Get("primary_group_id").(int)
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateSmbUser(m, user_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxSmbUserRead(d, meta)
+}
+
+func resourceIboxSmbUserDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteSmbUser(user_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,212 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+func resourceIboxTreeq() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxTreeqCreate,
+		Read:   resourceIboxTreeqRead,
+		Update: resourceIboxTreeqUpdate,
+		Delete: resourceIboxTreeqDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importFilesystemChild("treeq_id"),
+		},
+
+		CustomizeDiff: customizeQuotaDiff,
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"filesystem_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"path": {
+				Description: "Directory of the filesystem the quota applies to e.g. /projects/alpha",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"soft_capacity": {
+				Description: "Soft capacity limit in bytes, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"hard_capacity": {
+				Description: "Hard capacity limit in bytes, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"soft_inodes": {
+				Description: "Soft limit of the number of files and directories, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"hard_inodes": {
+				Description: "Hard limit of the number of files and directories, 0 means no limit",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"used_capacity": {
+				Description: "Capacity used under the path in bytes",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"used_inodes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// quotaLimit sends unset limits as null, which removes the limit on the array
+func quotaLimit(limit int) interface{} {
+	if limit == 0 {
+		return nil
+	}
+	return limit
+}
+
+// validateQuotaLimits checks that the soft limit of a quota is not above its hard limit
+func validateQuotaLimits(d *schema.ResourceDiff, soft string, hard string) error {
+	softLimit := d.Get(soft).(int)
+	hardLimit := d.Get(hard).(int)
+	if softLimit != 0 && hardLimit != 0 && softLimit > hardLimit {
+		return fmt.Errorf("[ERROR] %v: %v is greater than %v: %v", soft, softLimit, hard, hardLimit)
+	}
+	return nil
+}
+
+// customizeQuotaDiff validates the limits of treeqs and of user and group quotas
+func customizeQuotaDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateQuotaLimits(d, "soft_capacity", "hard_capacity"); err != nil {
+		return err
+	}
+	return validateQuotaLimits(d, "soft_inodes", "hard_inodes")
+}
+
+func resourceIboxTreeqCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	filesystem_id := d.Get("filesystem_id").(int)
+	newTreeq := Treeq{
+		Name:          d.Get("name").(string),
+		Path:          d.Get("path").(string),
+		Soft_capacity: d.Get("soft_capacity").(int),
+		Hard_capacity: d.Get("hard_capacity").(int),
+		Soft_inodes:   d.Get("soft_inodes").(int),
+		Hard_inodes:   d.Get("hard_inodes").(int),
+	}
+
+	treeq, err := client.CreateTreeq(filesystem_id, newTreeq)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(treeq.Id))
+
+	return resourceIboxTreeqRead(d, meta)
+}
+
+func resourceIboxTreeqRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	treeq_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+	filesystem_id := d.Get("filesystem_id").(int)
+
+	treeq, err := client.ReadTreeq(filesystem_id, treeq_id)
+	if err != nil {
+		return err
+	}
+	if treeq == nil {
+		log.Printf("[WARN] Probably the treeq was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", treeq.Name)
+	d.Set("path", treeq.Path)
+	d.Set("soft_capacity", treeq.Soft_capacity)
+	d.Set("hard_capacity", treeq.Hard_capacity)
+	d.Set("soft_inodes", treeq.Soft_inodes)
+	d.Set("hard_inodes", treeq.Hard_inodes)
+	d.Set("used_capacity", treeq.Used_capacity)
+	d.Set("used_inodes", treeq.Used_inodes)
+
+	return nil
+}
+
+func resourceIboxTreeqUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	treeq_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+	filesystem_id := d.Get("filesystem_id").(int)
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("name") {
+		m["name"] = d.Get("name").(string)
+	}
+	for _, k := range []string{"soft_capacity", "hard_capacity", "soft_inodes", "hard_inodes"} {
+		if d.HasChange(k) {
+			m[k] = quotaLimit(d.Get(k).(int))
+		}
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateTreeq(m, filesystem_id, treeq_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxTreeqRead(d, meta)
+}
+
+func resourceIboxTreeqDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	treeq_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteTreeq(d.Get("filesystem_id").(int), treeq_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,178 @@
+package ibox
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+var userRoles = []string{
+	"ADMIN",
+	"POOL_ADMIN",
+	"READ_ONLY",
+	"TECHNICIAN",
+}
+
+func resourceIboxUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIboxUserCreate,
+		Read:   resourceIboxUserRead,
+		Update: resourceIboxUserUpdate,
+		Delete: resourceIboxUserDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"role": {
+				Description:  "Role of the user ADMIN, POOL_ADMIN, READ_ONLY or TECHNICIAN, the pools of a POOL_ADMIN are set with the owners of ibox_pool",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringInList(userRoles, false),
+			},
+			"password": {
+				Description: "Password of the user, only its SHA256 hash is kept in the state",
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceIboxUserCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	newUser := User{
+		Name:     d.Get("name").(string),
+		Email:    d.Get("email").(string),
+		Role:     d.Get("role").(string),
+		Password: d.Get("password").(string),
+		Enabled:  d.Get("enabled").(bool),
+	}
+
+	user, err := client.CreateUser(newUser)
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(user.Id))
+
+	err = disableAfterCreate(d, func(m map[string]interface{}) error {
+		_, err := client.UpdateUser(m, user.Id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceIboxUserRead(d, meta)
+}
+
+func resourceIboxUserRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	user, err := client.ReadUser(user_id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		log.Printf("[WARN] Probably the user was deleted out of band, removing it from state")
+		d.SetId("")
+		return nil
+	}
+
+	return setReadFields(d, map[string]interface{}{
+		"name":    user.Name,
+		"email":   user.Email,
+		"role":    user.Role,
+		"enabled": user.Enabled,
+	})
+}
+
+func resourceIboxUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	var m map[string]interface{}
+	m = make(map[string]interface{})
+
+	if d.HasChange("email") {
+		m["email"] = d.Get("email").(string)
+	}
+	if d.HasChange("role") {
+		m["role"] = d.Get("role").(string)
+	}
+	if d.HasChange("password") {
+		m["password"] = d.Get("password").(string)
+	}
+	if d.HasChange("enabled") {
+		m["enabled"] = d.Get("enabled").(bool)
+	}
+	if len(m) > 0 {
+		_, err := client.UpdateUser(m, user_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIboxUserRead(d, meta)
+}
+
+func resourceIboxUserDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	user_id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("[ERROR] %v", err)
+	}
+
+	err = client.DeleteUser(user_id)
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -14,7 +14,10 @@ func resourceIboxVolume() *schema.Resource {
 		Update: resourceIboxVolumeUpdate,
 		Delete: resourceIboxVolumeDelete,
 
+		CustomizeDiff: resourceIboxVolumeCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
+			"system": systemSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -47,12 +50,17 @@ func resourceIboxVolume() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 			},
+			"metadata":     metadataSchema(),
+			"metadata_all": metadataAllSchema(),
 		},
 	}
 }
 
 func resourceIboxVolumeCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	newVolume := Volume{
 		Name:                d.Get("name").(string),
@@ -74,11 +82,14 @@ func resourceIboxVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(strconv.Itoa(volume.Id))
 
-	return nil
+	return createMetadata(d, client, volume.Id)
 }
 
 func resourceIboxVolumeRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	volume, err := client.ReadVolume(d.Id())
 	if err != nil {
@@ -87,15 +98,19 @@ func resourceIboxVolumeRead(d *schema.ResourceData, meta interface{}) error {
 	if volume == nil {
 		log.Printf("[WARN] Probably the volume was deleted out of band, removing it from state")
 		d.SetId("")
+		return nil
 	}
 	d.Set("name", volume.Name)
-	return nil
+	return readMetadata(d, client, volume.Id)
 }
 
 func resourceIboxVolumeDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
-	err := client.DeleteVolume(d.Id())
+	err = client.DeleteVolume(d.Id())
 	if err != nil {
 		return err
 	}
@@ -103,7 +118,10 @@ func resourceIboxVolumeDelete(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceIboxVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	volume_id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -117,6 +135,17 @@ func resourceIboxVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
 		var m map[string]interface{}
 		m = make(map[string]interface{})
 
+		if k == "metadata_all" {
+			continue
+		}
+		if k == "metadata" {
+			if err := updateMetadata(d, client, volume_id); err != nil {
+				return err
+			}
+			d.SetPartial(k)
+			continue
+		}
+
 		if d.HasChange(k) {
 			old_value, new_value := d.GetChange(k)
 			log.Printf("[DEBUG] %v has changed from: %v to: %v", k, old_value, new_value)
@@ -142,3 +171,10 @@ func resourceIboxVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
 	d.Partial(false)
 	return nil
 }
+
+func resourceIboxVolumeCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := requireFeatureIf(d, meta, "compression_enabled", "compression"); err != nil {
+		return err
+	}
+	return customizeMetadataDiff(d, meta)
+}
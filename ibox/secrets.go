@@ -0,0 +1,38 @@
+package ibox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// hashSecret keeps secrets out of the state, only their hash is stored
+func hashSecret(v interface{}) string {
+	secret := v.(string)
+	if secret == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// setReadFields stores the attributes read from the array. The array never returns passwords or
+// SNMP communities, they are left out of fields so the hash stored by hashSecret stays in the state.
+func setReadFields(d *schema.ResourceData, fields map[string]interface{}) error {
+	for k, v := range fields {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("[ERROR] Error setting %v: %#v", k, err)
+		}
+	}
+	return nil
+}
+
+// disableAfterCreate disables objects configured with enabled = false. The API drops enabled=false
+// from create requests, so the object is created enabled and disabled with the update right after.
+func disableAfterCreate(d *schema.ResourceData, update func(m map[string]interface{}) error) error {
+	if d.Get("enabled").(bool) {
+		return nil
+	}
+	return update(map[string]interface{}{"enabled": false})
+}
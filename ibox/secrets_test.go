@@ -0,0 +1,71 @@
+package ibox
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestHashSecret(t *testing.T) {
+	if hashSecret("") != "" {
+		t.Error("expected an empty secret to stay empty")
+	}
+	hash := hashSecret("123456")
+	if hash == "123456" || len(hash) != 64 {
+		t.Errorf("expected a SHA256 hex digest, got: %v", hash)
+	}
+	if hashSecret("123456") != hash {
+		t.Error("expected the hash to be stable")
+	}
+}
+
+func TestResourceIboxUserCreateDisabled(t *testing.T) {
+	cases := map[bool]int{
+		true:  0,
+		false: 1,
+	}
+	for enabled, updates := range cases {
+		api, client := newFakeApi(t, map[string]string{
+			"POST /users/": `{"id": 5, "name": "u1", "enabled": true}`,
+			"PUT /users/5": `{"id": 5, "name": "u1", "enabled": false}`,
+			"GET /users/5": `{"id": 5, "name": "u1", "role": "POOL_ADMIN", "enabled": ` + strconv.FormatBool(enabled) + `}`,
+		})
+		meta := &ClientPool{Default: client}
+
+		c, err := config.NewRawConfig(map[string]interface{}{
+			"name":     "u1",
+			"role":     "POOL_ADMIN",
+			"password": "123456",
+			"enabled":  enabled,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := resourceIboxUser()
+		diff, err := r.Diff(nil, terraform.NewResourceConfig(c), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, err := r.Apply(nil, diff, meta)
+		puts := api.requestsTo("PUT", "/users/5")
+		api.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(puts) != updates {
+			t.Errorf("enabled=%v: expected %v updates after create, got: %v", enabled, updates, puts)
+		}
+		if updates > 0 && puts[0].Body["enabled"] != false {
+			t.Errorf("enabled=%v: expected the user to be disabled, got: %v", enabled, puts[0].Body)
+		}
+		if got := state.Attributes["password"]; got != hashSecret("123456") {
+			t.Errorf("enabled=%v: expected the password hash to be kept in the state, got: %q", enabled, got)
+		}
+		if got := state.Attributes["enabled"]; got != strconv.FormatBool(enabled) {
+			t.Errorf("enabled=%v: enabled = %v in the state", enabled, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package ibox
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestNewRateLimiterDisabled(t *testing.T) {
+	if r := newRateLimiter(0, 5); r != nil {
+		t.Errorf("expected no limiter for 0 requests per second, got: %v", r)
+	}
+	var r *rateLimiter
+	r.Wait()
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	cases := []struct {
+		name     string
+		rate     float64
+		burst    int
+		tokens   float64
+		elapsed  time.Duration
+		expected float64
+	}{
+		{"refill at rate", 4, 10, 0, 500 * time.Millisecond, 1},
+		{"partial refill", 10, 10, 2, 250 * time.Millisecond, 3.5},
+		{"capped at burst", 4, 3, 0, time.Hour, 2},
+		{"burst below one", 4, 0, 0, time.Hour, 0},
+	}
+	for _, c := range cases {
+		r := newRateLimiter(c.rate, c.burst)
+		r.tokens = c.tokens
+		r.last = time.Now().Add(-c.elapsed)
+		r.Wait()
+		// a few milliseconds pass between setting last and Wait
+		if math.Abs(r.tokens-c.expected) > 0.1 {
+			t.Errorf("%v: tokens after Wait = %v, expected %v", c.name, r.tokens, c.expected)
+		}
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	r := newRateLimiter(20, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		r.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Millisecond {
+		t.Errorf("the burst of 3 requests was delayed by %v", elapsed)
+	}
+
+	start = time.Now()
+	r.Wait()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("the request after the burst was only delayed by %v, expected about 50ms", elapsed)
+	}
+}
+
+func TestSemaphoreDisabled(t *testing.T) {
+	s := newSemaphore(0)
+	if s != nil {
+		t.Errorf("expected no semaphore for 0 concurrent requests, got: %v", s)
+	}
+	s.Acquire()
+	s.Release()
+}
+
+// apiCallReturns fails the test when the call blocks, e.g. on a semaphore slot that was never released
+func apiCallReturns(t *testing.T, client *Client, name string) {
+	done := make(chan struct{})
+	go func() {
+		client.apiCall("GET", "/system", nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%v: API call blocked, the concurrency slot was not released", name)
+	}
+}
+
+func TestSemaphoreReleasedOnErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/invalid") {
+			w.Write([]byte("<html>not json</html>"))
+			return
+		}
+		w.WriteHeader(500)
+		w.Write([]byte(`{"error": {"code": "INTERNAL_ERROR"}}`))
+	}))
+	client, err := NewClient("admin", "123456", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetThrottle(0, 0, 1)
+
+	if _, _, err := client.apiCall("GET", "/invalid", nil); err == nil {
+		t.Error("expected an error for a non JSON response")
+	}
+	apiCallReturns(t, client, "non JSON response")
+
+	if _, resp, err := client.apiCall("GET", "/pools/1", nil); err != nil || resp.StatusCode != 500 {
+		t.Errorf("expected an API error response, got: %v %v", resp, err)
+	}
+	apiCallReturns(t, client, "API error response")
+
+	server.Close()
+	if _, _, err := client.apiCall("GET", "/pools/1", nil); err == nil {
+		t.Error("expected an error when the array is not reachable")
+	}
+	apiCallReturns(t, client, "unreachable array")
+
+	if n := len(client.inflight); n != 0 {
+		t.Errorf("%v concurrency slots are still taken", n)
+	}
+}
+
+func TestProviderThrottleEnvDefaults(t *testing.T) {
+	schemas := Provider().(*schema.Provider).Schema
+	cases := map[string]string{
+		"IBOX_REQUESTS_PER_SECOND":     "requests_per_second",
+		"IBOX_BURST":                   "burst",
+		"IBOX_MAX_CONCURRENT_REQUESTS": "max_concurrent_requests",
+	}
+	for env, key := range cases {
+		os.Setenv(env, "7")
+		v, err := schemas[key].DefaultValue()
+		os.Unsetenv(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != "7" {
+			t.Errorf("%v defaults to %v, expected the %v value", key, v, env)
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/inhies/go-bytesize"
 	"math"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -58,28 +60,96 @@ func validateMinPoolSize(v interface{}, k string) (ws []string, errors []error)
 	return
 }
 
+var (
+	iscsiInitiatorIqnRegex = regexp.MustCompile(`^iqn\.\d{4}-\d{2}\.([[:alnum:]-.]+)(:[^,;*&$|\s]+)?$`)
+	iscsiInitiatorEuiRegex = regexp.MustCompile(`^eui\.[0-9a-fA-F]{16}$`)
+	nvmeNqnRegex           = regexp.MustCompile(`^nqn\.\d{4}-\d{2}\.([[:alnum:]-.]+)(:[^,;*&$|\s]+)$`)
+	fcWWNRegex             = regexp.MustCompile(`^[0-9a-f]{16}$`)
+	// WWNs are accepted with colon or dash separators between the bytes, in any case
+	fcWWNLooseRegex = regexp.MustCompile(`^([0-9a-fA-F]{2}[:-]?){7}[0-9a-fA-F]{2}$`)
+)
+
 func validateIqn(v interface{}, k string) (ws []string, errors []error) {
-	var iscsiInitiatorIqnRegex = regexp.MustCompile(`iqn\.\d{4}-\d{2}\.([[:alnum:]-.]+)(:[^,;*&$|\s]+)$`)
-	if !iscsiInitiatorIqnRegex.MatchString(v.(string)) {
+	if err := myvalidateIqn(v); err != nil {
 		errors = append(errors, fmt.Errorf(
-			"%q IQN format is wrong", k))
+			"%q %v: %v", k, err, v))
 	}
 	return
 }
 
+// myvalidateIqn accepts iSCSI initiator names in the iqn. and eui. formats
 func myvalidateIqn(v interface{}) error {
-	var iscsiInitiatorIqnRegex = regexp.MustCompile(`iqn\.\d{4}-\d{2}\.([[:alnum:]-.]+)(:[^,;*&$|\s]+)$`)
-	if !iscsiInitiatorIqnRegex.MatchString(v.(string)) {
+	value := v.(string)
+	if !iscsiInitiatorIqnRegex.MatchString(value) && !iscsiInitiatorEuiRegex.MatchString(value) {
 		return fmt.Errorf("IQN format is wrong")
 	}
 	return nil
 }
 
+func validateNqn(v interface{}) error {
+	if !nvmeNqnRegex.MatchString(v.(string)) {
+		return fmt.Errorf("NQN format is wrong")
+	}
+	return nil
+}
+
 func validateFcWWN(v interface{}, k string) (ws []string, errors []error) {
-	var fcWWNRegex = regexp.MustCompile(`([0-9aA-fF]{16}`)
-	if !fcWWNRegex.MatchString(v.(string)) {
+	if !fcWWNRegex.MatchString(normalizeWWN(v.(string))) {
+		errors = append(errors, fmt.Errorf(
+			"%q FC WWN format is wrong: %v", k, v))
+	}
+	return
+}
+
+// normalizeWWN converts FC WWNs to the canonical form of the array, 16 lower case hex
+// digits without separators. Other addresses are returned unchanged.
+func normalizeWWN(address string) string {
+	if !fcWWNLooseRegex.MatchString(address) {
+		return address
+	}
+	address = strings.Replace(address, ":", "", -1)
+	address = strings.Replace(address, "-", "", -1)
+	return strings.ToLower(address)
+}
+
+// normalizePortAddress is the StateFunc of port addresses
+func normalizePortAddress(v interface{}) string {
+	return normalizeWWN(v.(string))
+}
+
+// validatePortAddress checks the address format of the given port type
+func validatePortAddress(port_type string, address string) error {
+	switch port_type {
+	case "FC":
+		if !fcWWNRegex.MatchString(normalizeWWN(address)) {
+			return fmt.Errorf("[ERROR] FC WWN format is wrong: %v, expected 16 hex digits e.g. 21100024ff913bff", address)
+		}
+	case "ISCSI":
+		if err := myvalidateIqn(address); err != nil {
+			return fmt.Errorf("[ERROR] %v: %v, expected iqn.yyyy-mm.naming-authority:name or eui.16 hex digits", err, address)
+		}
+	case "NVME":
+		if err := validateNqn(address); err != nil {
+			return fmt.Errorf("[ERROR] %v: %v, expected nqn.yyyy-mm.naming-authority:name", err, address)
+		}
+	}
+	return nil
+}
+
+func validateIPAddress(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if net.ParseIP(value) == nil {
+		errors = append(errors, fmt.Errorf(
+			"%q is not a valid IP address: %v", k, value))
+	}
+	return
+}
+
+func validateRFC3339(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
 		errors = append(errors, fmt.Errorf(
-			"%q FC WWN format is wrong", k))
+			"%q is not an RFC 3339 time e.g. 2018-01-02T15:04:05Z: %v", k, value))
 	}
 	return
 }
@@ -115,6 +185,17 @@ func validateIntegerGeqThan(threshold int) schema.SchemaValidateFunc {
 	}
 }
 
+func validateFloatGeqThan(threshold float64) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(float64)
+		if value < threshold {
+			errors = append(errors, fmt.Errorf(
+				"%q cannot be lower than %v", k, threshold))
+		}
+		return
+	}
+}
+
 func validateIntegerInRange(min, max int) schema.SchemaValidateFunc {
 	return func(v interface{}, k string) (ws []string, errors []error) {
 		value := v.(int)
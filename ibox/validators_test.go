@@ -0,0 +1,113 @@
+package ibox
+
+import (
+	"testing"
+)
+
+func TestNormalizeWWN(t *testing.T) {
+	cases := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{"canonical", "21100024ff913bff", "21100024ff913bff"},
+		{"colon", "21:10:00:24:ff:91:3b:ff", "21100024ff913bff"},
+		{"dash", "21-10-00-24-ff-91-3b-ff", "21100024ff913bff"},
+		{"upper case", "21100024FF913BFF", "21100024ff913bff"},
+		{"mixed case colon", "21:10:00:24:Ff:91:3B:fF", "21100024ff913bff"},
+		{"mixed separators", "21:10-00:24-ff:91-3b:ff", "21100024ff913bff"},
+		{"too short is unchanged", "21:10:00:24:FF:91:3B", "21:10:00:24:FF:91:3B"},
+		{"too long is unchanged", "21:10:00:24:FF:91:3B:FF:00", "21:10:00:24:FF:91:3B:FF:00"},
+		{"iqn is unchanged", "iqn.2005-03.org.open-iscsi:ABC", "iqn.2005-03.org.open-iscsi:ABC"},
+	}
+	for _, c := range cases {
+		if got := normalizeWWN(c.address); got != c.expected {
+			t.Errorf("%v: normalizeWWN(%q) = %q, expected %q", c.name, c.address, got, c.expected)
+		}
+	}
+}
+
+func TestValidatePortAddress(t *testing.T) {
+	cases := []struct {
+		name      string
+		port_type string
+		address   string
+		valid     bool
+	}{
+		{"fc canonical", "FC", "21100024ff913bff", true},
+		{"fc colon", "FC", "21:10:00:24:ff:91:3b:ff", true},
+		{"fc dash upper case", "FC", "21-10-00-24-FF-91-3B-FF", true},
+		{"fc 15 digits", "FC", "21100024ff913bf", false},
+		{"fc 17 digits", "FC", "21100024ff913bff0", false},
+		{"fc 7 bytes", "FC", "21:10:00:24:ff:91:3b", false},
+		{"fc 9 bytes", "FC", "21:10:00:24:ff:91:3b:ff:00", false},
+		{"fc not hex", "FC", "21100024ff913bgg", false},
+		{"fc iqn", "FC", "iqn.1994-05.com.redhat:1a2b3c", false},
+		{"iscsi iqn", "ISCSI", "iqn.1994-05.com.redhat:1a2b3c", true},
+		{"iscsi iqn without name", "ISCSI", "iqn.1994-05.com.redhat", true},
+		{"iscsi eui", "ISCSI", "eui.02004567A425678D", true},
+		{"iscsi eui short", "ISCSI", "eui.02004567A425678", false},
+		{"iscsi eui not hex", "ISCSI", "eui.02004567A425678X", false},
+		{"iscsi bad date", "ISCSI", "iqn.94-05.com.redhat:1a2b3c", false},
+		{"iscsi wwn", "ISCSI", "21100024ff913bff", false},
+		{"nvme nqn", "NVME", "nqn.2014-08.org.nvmexpress:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", true},
+		{"nvme nqn without name", "NVME", "nqn.2014-08.org.nvmexpress", false},
+		{"nvme iqn", "NVME", "iqn.1994-05.com.redhat:1a2b3c", false},
+		{"unknown type is not checked", "OTHER", "anything", true},
+	}
+	for _, c := range cases {
+		err := validatePortAddress(c.port_type, c.address)
+		if c.valid && err != nil {
+			t.Errorf("%v: unexpected error for %v %q: %v", c.name, c.port_type, c.address, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%v: expected an error for %v %q", c.name, c.port_type, c.address)
+		}
+	}
+}
+
+func TestMyvalidateIqn(t *testing.T) {
+	cases := map[string]bool{
+		"iqn.1994-05.com.redhat:1a2b3c":         true,
+		"iqn.2005-03.org.open-iscsi:d3e7a4b1c2": true,
+		"iqn.1991-05.com.microsoft:host-01.lab": true,
+		"eui.02004567A425678D":                  true,
+		"eui.02004567a425678d":                  true,
+		"IQN.1994-05.com.redhat:1a2b3c":         false,
+		"iqn.1994-5.com.redhat:1a2b3c":          false,
+		"iqn.1994-05.com.redhat:bad name":       false,
+		"iqn.1994-05.com.redhat:a,b":            false,
+		"eui.02004567A425678D00":                false,
+		"":                                      false,
+	}
+	for iqn, valid := range cases {
+		err := myvalidateIqn(iqn)
+		if valid && err != nil {
+			t.Errorf("unexpected error for %q: %v", iqn, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("expected an error for %q", iqn)
+		}
+	}
+}
+
+func TestValidateNqn(t *testing.T) {
+	cases := map[string]bool{
+		"nqn.2014-08.org.nvmexpress:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6": true,
+		"nqn.2014-08.com.example:nvme:host01":                                  true,
+		"nqn.2014-08.org.nvmexpress":                                           false,
+		"nqn.14-08.org.nvmexpress:uuid:f81d4fae":                               false,
+		"nqn.2014-08.com.example:bad name":                                     false,
+		"iqn.2014-08.com.example:host01":                                       false,
+		"":                                                                     false,
+	}
+	for nqn, valid := range cases {
+		err := validateNqn(nqn)
+		if valid && err != nil {
+			t.Errorf("unexpected error for %q: %v", nqn, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("expected an error for %q", nqn)
+		}
+	}
+}